@@ -0,0 +1,52 @@
+// Package backends abstracts the full-text/fuzzy search engine that powers
+// world lookup so the rest of the codebase (SuggestionsService,
+// WorldsRepository callers, RankingService) does not need to know whether
+// candidates came from pg_trgm, Bleve, Elasticsearch, or Meilisearch.
+package backends
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/worlds-search/backend/models"
+)
+
+// ErrUnsupported reports that a backend is not available for an
+// operation. None of the adapters in this package return it;
+// CompareService returns it for a lab mode whose backend wasn't configured.
+var ErrUnsupported = errors.New("backends: operation not supported by this backend")
+
+// Match is a single candidate returned by a backend query. Score is
+// normalized to roughly the 0-1 range where possible so RankingService can
+// blend it with other signals, but backends that expose a native relevance
+// score (BM25, etc.) may return values outside that range - callers should
+// treat Score as backend-relative, not globally comparable.
+type Match struct {
+	World models.World
+	Score float64
+}
+
+// SearchBackend is implemented by every full-text search engine the
+// suggestions/search pipeline can be configured to use. Adapters are
+// expected to be safe for concurrent use.
+type SearchBackend interface {
+	// PrefixMatch returns worlds whose title starts with prefix.
+	PrefixMatch(ctx context.Context, prefix string, limit int) ([]Match, error)
+
+	// FuzzyMatch returns worlds similar to keyword, tolerating typos.
+	FuzzyMatch(ctx context.Context, keyword string, limit int) ([]Match, error)
+
+	// FullTextSearch runs a relevance-ranked query (BM25 or equivalent)
+	// over title and description.
+	FullTextSearch(ctx context.Context, query string, limit int) ([]Match, error)
+
+	// IndexWorld upserts a world document into the backend's index.
+	IndexWorld(ctx context.Context, world models.World) error
+
+	// DeleteWorld removes a world document from the index.
+	DeleteWorld(ctx context.Context, id uuid.UUID) error
+
+	// Name identifies the backend for logging/metrics (e.g. "pg_trgm").
+	Name() string
+}
@@ -0,0 +1,110 @@
+package backends
+
+import (
+	"context"
+
+	"github.com/blevesearch/bleve/v2"
+	"github.com/blevesearch/bleve/v2/search/query"
+	"github.com/google/uuid"
+	"github.com/worlds-search/backend/models"
+)
+
+// bleveDoc is the flattened document shape stored in the Bleve index.
+type bleveDoc struct {
+	Title       string `json:"title"`
+	Description string `json:"description"`
+}
+
+// BleveBackend is an embedded, in-process full-text index. It's the
+// cheapest backend to run (no extra service) and is a good default for
+// local dev and small deployments.
+type BleveBackend struct {
+	index bleve.Index
+}
+
+// NewBleveBackend opens (or creates) a Bleve index at path. Pass "" to use
+// an in-memory index, which is useful for tests.
+func NewBleveBackend(path string) (*BleveBackend, error) {
+	var index bleve.Index
+	var err error
+
+	if path == "" {
+		index, err = bleve.NewMemOnly(bleve.NewIndexMapping())
+	} else {
+		index, err = bleve.Open(path)
+		if err != nil {
+			index, err = bleve.New(path, bleve.NewIndexMapping())
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &BleveBackend{index: index}, nil
+}
+
+func (b *BleveBackend) Name() string { return "bleve" }
+
+func (b *BleveBackend) PrefixMatch(ctx context.Context, prefix string, limit int) ([]Match, error) {
+	query := bleve.NewPrefixQuery(prefix)
+	query.SetField("title")
+	return b.runQuery(query, limit)
+}
+
+func (b *BleveBackend) FuzzyMatch(ctx context.Context, keyword string, limit int) ([]Match, error) {
+	query := bleve.NewMatchQuery(keyword)
+	query.SetField("title")
+	query.Fuzziness = 2
+	return b.runQuery(query, limit)
+}
+
+func (b *BleveBackend) FullTextSearch(ctx context.Context, query string, limit int) ([]Match, error) {
+	q := bleve.NewQueryStringQuery(query)
+	return b.runQuery(q, limit)
+}
+
+func (b *BleveBackend) runQuery(q query.Query, limit int) ([]Match, error) {
+	req := bleve.NewSearchRequestOptions(q, limit, 0, false)
+	req.Fields = []string{"title", "description"}
+	req.Highlight = bleve.NewHighlight()
+
+	result, err := b.index.Search(req)
+	if err != nil {
+		return nil, err
+	}
+
+	matches := make([]Match, 0, len(result.Hits))
+	for _, hit := range result.Hits {
+		id, err := uuid.Parse(hit.ID)
+		if err != nil {
+			continue
+		}
+		matches = append(matches, Match{
+			World: models.World{
+				ID:          id,
+				Title:       fieldString(hit.Fields, "title"),
+				Description: fieldString(hit.Fields, "description"),
+			},
+			Score: hit.Score,
+		})
+	}
+	return matches, nil
+}
+
+func (b *BleveBackend) IndexWorld(ctx context.Context, world models.World) error {
+	return b.index.Index(world.ID.String(), bleveDoc{
+		Title:       world.Title,
+		Description: world.Description,
+	})
+}
+
+func (b *BleveBackend) DeleteWorld(ctx context.Context, id uuid.UUID) error {
+	return b.index.Delete(id.String())
+}
+
+func fieldString(fields map[string]interface{}, key string) string {
+	if s, ok := fields[key].(string); ok {
+		return s
+	}
+	return ""
+}
@@ -0,0 +1,143 @@
+package backends
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/google/uuid"
+	"github.com/olivere/elastic/v7"
+	"github.com/worlds-search/backend/models"
+)
+
+const elasticWorldsIndex = "worlds"
+
+// elasticDoc is the JSON shape stored in the Elasticsearch index. It mirrors
+// the worlds table so a bulk backfill can stream rows in directly.
+type elasticDoc struct {
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	CreatedAt   string `json:"createdAt"`
+}
+
+// ElasticBackend adapts Elasticsearch (or an OpenSearch cluster, which
+// speaks the same 7.x query DSL) to SearchBackend using a match query with
+// fuzziness:AUTO for fuzzy lookups and a bool query for full-text relevance
+// ranking.
+type ElasticBackend struct {
+	client *elastic.Client
+	name   string
+}
+
+// NewElasticBackend connects to an Elasticsearch cluster at url and ensures
+// the worlds index exists.
+func NewElasticBackend(ctx context.Context, url string) (*ElasticBackend, error) {
+	return newElasticLikeBackend(ctx, url, "elasticsearch", true)
+}
+
+// NewOpenSearchBackend connects to an OpenSearch cluster at url using the
+// same olivere/elastic v7 client as NewElasticBackend. Healthcheck is
+// disabled because olivere/elastic's handshake rejects the "opensearch"
+// version string OpenSearch reports in its root response; the rest of the
+// 7.x query DSL is wire-compatible.
+func NewOpenSearchBackend(ctx context.Context, url string) (*ElasticBackend, error) {
+	return newElasticLikeBackend(ctx, url, "opensearch", false)
+}
+
+func newElasticLikeBackend(ctx context.Context, url, name string, healthcheck bool) (*ElasticBackend, error) {
+	client, err := elastic.NewClient(
+		elastic.SetURL(url),
+		elastic.SetSniff(false),
+		elastic.SetHealthcheck(healthcheck),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	exists, err := client.IndexExists(elasticWorldsIndex).Do(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		if _, err := client.CreateIndex(elasticWorldsIndex).Do(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	return &ElasticBackend{client: client, name: name}, nil
+}
+
+func (b *ElasticBackend) Name() string { return b.name }
+
+func (b *ElasticBackend) PrefixMatch(ctx context.Context, prefix string, limit int) ([]Match, error) {
+	query := elastic.NewMatchPhrasePrefixQuery("title", prefix)
+	return b.runQuery(ctx, query, limit)
+}
+
+func (b *ElasticBackend) FuzzyMatch(ctx context.Context, keyword string, limit int) ([]Match, error) {
+	query := elastic.NewMatchQuery("title", keyword).Fuzziness("AUTO")
+	return b.runQuery(ctx, query, limit)
+}
+
+func (b *ElasticBackend) FullTextSearch(ctx context.Context, query string, limit int) ([]Match, error) {
+	boolQuery := elastic.NewBoolQuery().
+		Should(elastic.NewMatchQuery("title", query).Boost(2)).
+		Should(elastic.NewMatchQuery("description", query))
+	return b.runQuery(ctx, boolQuery, limit)
+}
+
+func (b *ElasticBackend) runQuery(ctx context.Context, query elastic.Query, limit int) ([]Match, error) {
+	result, err := b.client.Search().
+		Index(elasticWorldsIndex).
+		Query(query).
+		Size(limit).
+		Do(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	matches := make([]Match, 0, len(result.Hits.Hits))
+	for _, hit := range result.Hits.Hits {
+		id, err := uuid.Parse(hit.Id)
+		if err != nil {
+			continue
+		}
+		var doc elasticDoc
+		if err := json.Unmarshal(hit.Source, &doc); err != nil {
+			continue
+		}
+		score := 0.0
+		if hit.Score != nil {
+			score = *hit.Score
+		}
+		matches = append(matches, Match{
+			World: models.World{ID: id, Title: doc.Title, Description: doc.Description},
+			Score: score,
+		})
+	}
+	return matches, nil
+}
+
+func (b *ElasticBackend) IndexWorld(ctx context.Context, world models.World) error {
+	doc := elasticDoc{
+		Title:       world.Title,
+		Description: world.Description,
+		CreatedAt:   world.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+	}
+	_, err := b.client.Index().
+		Index(elasticWorldsIndex).
+		Id(world.ID.String()).
+		BodyJson(doc).
+		Do(ctx)
+	return err
+}
+
+func (b *ElasticBackend) DeleteWorld(ctx context.Context, id uuid.UUID) error {
+	_, err := b.client.Delete().
+		Index(elasticWorldsIndex).
+		Id(id.String()).
+		Do(ctx)
+	if elastic.IsNotFound(err) {
+		return nil
+	}
+	return err
+}
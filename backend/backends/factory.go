@@ -0,0 +1,41 @@
+package backends
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/worlds-search/backend/repositories"
+)
+
+// Options holds the connection settings for every backend the factory
+// knows how to build. Only the fields relevant to the selected Kind need
+// to be populated.
+type Options struct {
+	Kind string // "pg_trgm" | "bleve" | "elastic" | "opensearch" | "meili"
+
+	BlevePath string // "" for an in-memory index
+
+	ElasticURL string
+
+	MeiliHost   string
+	MeiliAPIKey string
+}
+
+// New builds the SearchBackend selected by opts.Kind. worldsRepo is only
+// used by the pg_trgm backend.
+func New(ctx context.Context, opts Options, worldsRepo *repositories.WorldsRepository) (SearchBackend, error) {
+	switch opts.Kind {
+	case "", "pg_trgm":
+		return NewPGTrgmBackend(worldsRepo), nil
+	case "bleve":
+		return NewBleveBackend(opts.BlevePath)
+	case "elastic":
+		return NewElasticBackend(ctx, opts.ElasticURL)
+	case "opensearch":
+		return NewOpenSearchBackend(ctx, opts.ElasticURL)
+	case "meili":
+		return NewMeiliBackend(opts.MeiliHost, opts.MeiliAPIKey)
+	default:
+		return nil, fmt.Errorf("backends: unknown SEARCH_BACKEND %q", opts.Kind)
+	}
+}
@@ -0,0 +1,113 @@
+package backends
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/google/uuid"
+	"github.com/meilisearch/meilisearch-go"
+	"github.com/worlds-search/backend/models"
+)
+
+const meiliWorldsIndex = "worlds"
+
+// meiliDoc is the JSON document shape stored in the Meilisearch index.
+// Meilisearch requires a primary key field, so the world ID is stored as
+// "id" rather than relying on a separate document ID.
+type meiliDoc struct {
+	ID          string `json:"id"`
+	Title       string `json:"title"`
+	Description string `json:"description"`
+}
+
+// MeiliBackend adapts Meilisearch to SearchBackend. Meilisearch's default
+// search already does typo-tolerant prefix matching, so PrefixMatch and
+// FuzzyMatch both route through the same search call with different
+// query strings.
+type MeiliBackend struct {
+	client meilisearch.ServiceManager
+	index  meilisearch.IndexManager
+}
+
+// NewMeiliBackend connects to a Meilisearch instance at host and ensures
+// the worlds index exists with "id" as its primary key.
+func NewMeiliBackend(host, apiKey string) (*MeiliBackend, error) {
+	client := meilisearch.New(host, meilisearch.WithAPIKey(apiKey))
+
+	if _, err := client.CreateIndex(&meilisearch.IndexConfig{
+		Uid:        meiliWorldsIndex,
+		PrimaryKey: "id",
+	}); err != nil {
+		return nil, err
+	}
+
+	return &MeiliBackend{
+		client: client,
+		index:  client.Index(meiliWorldsIndex),
+	}, nil
+}
+
+func (b *MeiliBackend) Name() string { return "meilisearch" }
+
+func (b *MeiliBackend) PrefixMatch(ctx context.Context, prefix string, limit int) ([]Match, error) {
+	return b.search(prefix, limit)
+}
+
+func (b *MeiliBackend) FuzzyMatch(ctx context.Context, keyword string, limit int) ([]Match, error) {
+	// Meilisearch applies typo tolerance to every query by default, so a
+	// fuzzy lookup is just a normal search.
+	return b.search(keyword, limit)
+}
+
+func (b *MeiliBackend) FullTextSearch(ctx context.Context, query string, limit int) ([]Match, error) {
+	return b.search(query, limit)
+}
+
+func (b *MeiliBackend) search(query string, limit int) ([]Match, error) {
+	result, err := b.index.Search(query, &meilisearch.SearchRequest{
+		Limit:                int64(limit),
+		ShowRankingScore:     true,
+		AttributesToRetrieve: []string{"id", "title", "description"},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	matches := make([]Match, 0, len(result.Hits))
+	for _, hit := range result.Hits {
+		var idStr, title, description string
+		if err := json.Unmarshal(hit["id"], &idStr); err != nil {
+			continue
+		}
+		id, err := uuid.Parse(idStr)
+		if err != nil {
+			continue
+		}
+		_ = json.Unmarshal(hit["title"], &title)
+		_ = json.Unmarshal(hit["description"], &description)
+
+		var score float64
+		_ = json.Unmarshal(hit["_rankingScore"], &score)
+
+		matches = append(matches, Match{
+			World: models.World{ID: id, Title: title, Description: description},
+			Score: score,
+		})
+	}
+	return matches, nil
+}
+
+func (b *MeiliBackend) IndexWorld(ctx context.Context, world models.World) error {
+	doc := meiliDoc{
+		ID:          world.ID.String(),
+		Title:       world.Title,
+		Description: world.Description,
+	}
+	_, err := b.index.AddDocuments([]meiliDoc{doc}, nil)
+	return err
+}
+
+func (b *MeiliBackend) DeleteWorld(ctx context.Context, id uuid.UUID) error {
+	_, err := b.index.DeleteDocument(id.String(), nil)
+	return err
+}
@@ -0,0 +1,73 @@
+package backends
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/worlds-search/backend/models"
+	"github.com/worlds-search/backend/repositories"
+)
+
+// PGTrgmBackend adapts the existing WorldsRepository (pg_trgm/ILIKE queries)
+// to the SearchBackend interface. It does no indexing of its own - the
+// "index" is just the worlds table plus its trigram GIN index, so
+// IndexWorld/DeleteWorld are no-ops.
+type PGTrgmBackend struct {
+	worldsRepo *repositories.WorldsRepository
+}
+
+// NewPGTrgmBackend creates a new PGTrgmBackend.
+func NewPGTrgmBackend(worldsRepo *repositories.WorldsRepository) *PGTrgmBackend {
+	return &PGTrgmBackend{worldsRepo: worldsRepo}
+}
+
+func (b *PGTrgmBackend) Name() string { return "pg_trgm" }
+
+func (b *PGTrgmBackend) PrefixMatch(ctx context.Context, prefix string, limit int) ([]Match, error) {
+	worlds, err := b.worldsRepo.SearchByPrefix(ctx, prefix, limit)
+	if err != nil {
+		return nil, err
+	}
+	matches := make([]Match, len(worlds))
+	for i, w := range worlds {
+		matches[i] = Match{World: w, Score: 1.0}
+	}
+	return matches, nil
+}
+
+func (b *PGTrgmBackend) FuzzyMatch(ctx context.Context, keyword string, limit int) ([]Match, error) {
+	results, err := b.worldsRepo.SearchByFuzzy(ctx, keyword, limit)
+	if err != nil {
+		return nil, err
+	}
+	return toMatches(results), nil
+}
+
+// FullTextSearch falls back to the combined prefix/fuzzy/contains query -
+// pg_trgm has no BM25-style relevance ranking of its own. The SearchBackend
+// interface has no per-query fuzziness knob yet, so this always runs at
+// models.FuzzyHigh (today's default behavior).
+func (b *PGTrgmBackend) FullTextSearch(ctx context.Context, query string, limit int) ([]Match, error) {
+	results, err := b.worldsRepo.SearchCombined(ctx, query, limit, models.FuzzyHigh)
+	if err != nil {
+		return nil, err
+	}
+	return toMatches(results), nil
+}
+
+func (b *PGTrgmBackend) IndexWorld(ctx context.Context, world models.World) error {
+	// The worlds table itself is the index; nothing to push separately.
+	return nil
+}
+
+func (b *PGTrgmBackend) DeleteWorld(ctx context.Context, id uuid.UUID) error {
+	return nil
+}
+
+func toMatches(results []repositories.WorldWithSimilarity) []Match {
+	matches := make([]Match, len(results))
+	for i, r := range results {
+		matches[i] = Match{World: r.World, Score: r.Similarity}
+	}
+	return matches
+}
@@ -0,0 +1,138 @@
+package main
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/worlds-search/backend/config"
+)
+
+// ObjectStore archives a raw batch of drained log records for durability
+// beyond Postgres - e.g. reprocessing after a schema change or cold
+// analytics that shouldn't run against the primary database. LocalDirStore
+// and S3Store are the two archiving implementations (NoopObjectStore
+// discards), selected by STORAGE_BACKEND (see config.Config.StorageBackend).
+type ObjectStore interface {
+	Archive(ctx context.Context, key string, batch []byte) error
+}
+
+// NoopObjectStore discards every batch. Used when STORAGE_BACKEND isn't
+// set - archival is optional, Postgres remains the system of record.
+type NoopObjectStore struct{}
+
+func (NoopObjectStore) Archive(ctx context.Context, key string, batch []byte) error { return nil }
+
+// archiveObjectName builds the gzip ndjson object name a batch is archived
+// under, partitioned by UTC date and hour so downstream analytics can scan
+// a bounded time range instead of a single flat directory/prefix, e.g.
+// "impressions/2024-07-26/13/impressions-1721998800000000000.ndjson.gz".
+func archiveObjectName(key string, at time.Time) string {
+	at = at.UTC()
+	return fmt.Sprintf("%s/%s/%s/%s-%d.ndjson.gz",
+		key, at.Format("2006-01-02"), at.Format("15"), key, at.UnixNano())
+}
+
+// gzipBatch compresses a raw ndjson batch before it's written to either
+// store - archived volumes are large enough over time that shipping them
+// uncompressed would be wasteful.
+func gzipBatch(raw []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	gw := gzip.NewWriter(&buf)
+	if _, err := gw.Write(raw); err != nil {
+		return nil, err
+	}
+	if err := gw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// LocalDirStore writes each batch as a gzipped, date/hour-partitioned
+// ndjson file under Dir. It stands in for a real object store in
+// environments that don't have one wired up yet, e.g. local dev.
+type LocalDirStore struct {
+	Dir string
+}
+
+func NewLocalDirStore(dir string) (*LocalDirStore, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	return &LocalDirStore{Dir: dir}, nil
+}
+
+func (s *LocalDirStore) Archive(ctx context.Context, key string, batch []byte) error {
+	compressed, err := gzipBatch(batch)
+	if err != nil {
+		return err
+	}
+
+	path := filepath.Join(s.Dir, filepath.FromSlash(archiveObjectName(key, time.Now())))
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, compressed, 0o644)
+}
+
+// S3Store archives batches to an S3 (or S3-compatible, e.g. MinIO) bucket.
+// Bucket/Endpoint/Region/credentials/Prefix come from config.Config's
+// StorageS3* fields.
+type S3Store struct {
+	client *s3.Client
+	bucket string
+	prefix string
+}
+
+// NewS3Store builds an S3Store from cfg. cfg.StorageS3Endpoint is only
+// needed to point at a non-AWS S3-compatible target like MinIO; leave it
+// empty to use AWS's normal endpoint resolution.
+func NewS3Store(ctx context.Context, cfg *config.Config) (*S3Store, error) {
+	if cfg.StorageS3Bucket == "" {
+		return nil, fmt.Errorf("archive: STORAGE_S3_BUCKET is required when STORAGE_BACKEND=s3")
+	}
+
+	awsCfg, err := awsconfig.LoadDefaultConfig(ctx,
+		awsconfig.WithRegion(cfg.StorageS3Region),
+		awsconfig.WithCredentialsProvider(credentials.NewStaticCredentialsProvider(
+			cfg.StorageS3AccessKey, cfg.StorageS3SecretKey, "",
+		)),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("archive: loading AWS config: %w", err)
+	}
+
+	client := s3.NewFromConfig(awsCfg, func(o *s3.Options) {
+		if cfg.StorageS3Endpoint != "" {
+			o.BaseEndpoint = aws.String(cfg.StorageS3Endpoint)
+		}
+		o.UsePathStyle = cfg.StorageS3UsePathStyle
+	})
+
+	return &S3Store{client: client, bucket: cfg.StorageS3Bucket, prefix: cfg.StorageS3Prefix}, nil
+}
+
+func (s *S3Store) Archive(ctx context.Context, key string, batch []byte) error {
+	compressed, err := gzipBatch(batch)
+	if err != nil {
+		return err
+	}
+
+	objectKey := s.prefix + archiveObjectName(key, time.Now())
+	_, err = s.client.PutObject(ctx, &s3.PutObjectInput{
+		Bucket:          aws.String(s.bucket),
+		Key:             aws.String(objectKey),
+		Body:            bytes.NewReader(compressed),
+		ContentType:     aws.String("application/x-ndjson"),
+		ContentEncoding: aws.String("gzip"),
+	})
+	return err
+}
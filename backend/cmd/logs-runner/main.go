@@ -0,0 +1,197 @@
+// Command logs-runner drains the Redis-buffered impression/click queues
+// (repositories.ImpressionQueueKey, repositories.ClickQueueKey) into
+// Postgres in batches, with an optional archival copy via ObjectStore.
+//
+// It only has work to do once the API process is configured to enqueue
+// through repositories.RedisLogQueue instead of repositories.ImpressionSink
+// - see RedisLogQueue's doc comment for why that's an opt-in swap rather
+// than the default.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/worlds-search/backend/config"
+	"github.com/worlds-search/backend/db"
+	"github.com/worlds-search/backend/logging"
+	"github.com/worlds-search/backend/repositories"
+)
+
+const (
+	drainBatchSize = 200
+	// idlePollInterval is how long the runner sleeps after finding both
+	// queues empty, to avoid hammering Redis with LPOPs.
+	idlePollInterval = 500 * time.Millisecond
+)
+
+func main() {
+	cfg := config.Load()
+	logging.Init(cfg.LogLevel)
+	logger := logging.For("logsrunner")
+
+	logger.Info("starting logs-runner")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	database, err := db.New(ctx, cfg.PostgresDSN, cfg.RedisAddr)
+	if err != nil {
+		logger.Error("failed to connect to databases", "err", err)
+		os.Exit(1)
+	}
+	defer database.Close()
+
+	logsRepo := repositories.NewLogsRepository(database.Pool, database.Redis)
+
+	var archive ObjectStore = NoopObjectStore{}
+	switch cfg.StorageBackend {
+	case "s3":
+		store, err := NewS3Store(ctx, cfg)
+		if err != nil {
+			logger.Error("failed to initialize STORAGE_BACKEND=s3", "err", err)
+			os.Exit(1)
+		}
+		archive = store
+		logger.Info("archiving drained batches to s3", "bucket", cfg.StorageS3Bucket, "prefix", cfg.StorageS3Prefix)
+	case "local":
+		dir := cfg.StorageDir
+		if dir == "" {
+			dir = "./logs-archive"
+		}
+		store, err := NewLocalDirStore(dir)
+		if err != nil {
+			logger.Error("failed to initialize STORAGE_BACKEND=local", "err", err)
+			os.Exit(1)
+		}
+		archive = store
+		logger.Info("archiving drained batches to local dir", "dir", dir)
+	case "":
+		// Archival disabled; Postgres remains the system of record.
+	default:
+		logger.Error("unknown STORAGE_BACKEND (want \"\", \"local\", or \"s3\")", "storageBackend", cfg.StorageBackend)
+		os.Exit(1)
+	}
+
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+
+	go func() {
+		<-quit
+		logger.Info("shutting down logs-runner")
+		cancel()
+	}()
+
+	runDrainLoop(ctx, database, logsRepo, archive)
+
+	logger.Info("logs-runner exited")
+}
+
+// runDrainLoop alternates draining the impression and click queues in
+// batches of up to drainBatchSize until ctx is canceled, sleeping
+// idlePollInterval whenever both queues come back empty.
+func runDrainLoop(ctx context.Context, database *db.DB, logsRepo *repositories.LogsRepository, archive ObjectStore) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		default:
+		}
+
+		impressions := drainImpressions(ctx, database, logsRepo, archive)
+		clicks := drainClicks(ctx, database, logsRepo, archive)
+
+		if impressions == 0 && clicks == 0 {
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(idlePollInterval):
+			}
+		}
+	}
+}
+
+func drainImpressions(ctx context.Context, database *db.DB, logsRepo *repositories.LogsRepository, archive ObjectStore) int {
+	logger := logging.For("logsrunner")
+
+	raw, err := database.Redis.LPopCount(ctx, repositories.ImpressionQueueKey, drainBatchSize).Result()
+	if err != nil && !errors.Is(err, redis.Nil) {
+		logger.Warn("failed to drain impression queue", "err", err)
+		return 0
+	}
+	if len(raw) == 0 {
+		return 0
+	}
+
+	records := make([]repositories.ImpressionRecord, 0, len(raw))
+	for _, payload := range raw {
+		var rec repositories.ImpressionRecord
+		if err := json.Unmarshal([]byte(payload), &rec); err != nil {
+			logger.Warn("dropping malformed impression record", "err", err)
+			continue
+		}
+		records = append(records, rec)
+	}
+
+	if err := logsRepo.InsertImpressionsBatch(ctx, records); err != nil {
+		logger.Warn("failed to insert impression batch", "batchSize", len(records), "err", err)
+		return len(raw)
+	}
+
+	if err := archive.Archive(ctx, "impressions", joinRaw(raw)); err != nil {
+		logger.Warn("failed to archive impression batch", "err", err)
+	}
+
+	return len(raw)
+}
+
+func drainClicks(ctx context.Context, database *db.DB, logsRepo *repositories.LogsRepository, archive ObjectStore) int {
+	logger := logging.For("logsrunner")
+
+	raw, err := database.Redis.LPopCount(ctx, repositories.ClickQueueKey, drainBatchSize).Result()
+	if err != nil && !errors.Is(err, redis.Nil) {
+		logger.Warn("failed to drain click queue", "err", err)
+		return 0
+	}
+	if len(raw) == 0 {
+		return 0
+	}
+
+	records := make([]repositories.ClickRecord, 0, len(raw))
+	for _, payload := range raw {
+		var rec repositories.ClickRecord
+		if err := json.Unmarshal([]byte(payload), &rec); err != nil {
+			logger.Warn("dropping malformed click record", "err", err)
+			continue
+		}
+		records = append(records, rec)
+	}
+
+	if err := logsRepo.InsertClicksBatch(ctx, records); err != nil {
+		logger.Warn("failed to insert click batch", "batchSize", len(records), "err", err)
+		return len(raw)
+	}
+
+	if err := archive.Archive(ctx, "clicks", joinRaw(raw)); err != nil {
+		logger.Warn("failed to archive click batch", "err", err)
+	}
+
+	return len(raw)
+}
+
+// joinRaw newline-joins the raw JSON payloads LPopCount returned into a
+// single ndjson blob for ObjectStore.Archive.
+func joinRaw(raw []string) []byte {
+	out := make([]byte, 0, len(raw)*64)
+	for _, r := range raw {
+		out = append(out, r...)
+		out = append(out, '\n')
+	}
+	return out
+}
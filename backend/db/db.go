@@ -2,11 +2,11 @@ package db
 
 import (
 	"context"
-	"log"
 	"time"
 
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/redis/go-redis/v9"
+	"github.com/worlds-search/backend/logging"
 )
 
 // DB holds database connections
@@ -27,7 +27,12 @@ func New(ctx context.Context, postgresDSN, redisAddr string) (*DB, error) {
 	poolConfig.MinConns = 5
 	poolConfig.MaxConnLifetime = time.Hour
 	poolConfig.MaxConnIdleTime = 30 * time.Minute
-	
+
+	// Every SQL statement is traced through logging.QueryTracer; it's a
+	// no-op in terms of volume unless LOG_LEVEL_DB=debug is set, since
+	// query/commandTag logging happens at debug level.
+	poolConfig.ConnConfig.Tracer = logging.NewQueryTracer()
+
 	pool, err := pgxpool.NewWithConfig(ctx, poolConfig)
 	if err != nil {
 		return nil, err
@@ -38,8 +43,8 @@ func New(ctx context.Context, postgresDSN, redisAddr string) (*DB, error) {
 		pool.Close()
 		return nil, err
 	}
-	log.Println("✓ Connected to PostgreSQL")
-	
+	logging.For("db").Info("connected to PostgreSQL")
+
 	// Connect to Redis
 	rdb := redis.NewClient(&redis.Options{
 		Addr:         redisAddr,
@@ -54,8 +59,8 @@ func New(ctx context.Context, postgresDSN, redisAddr string) (*DB, error) {
 		pool.Close()
 		return nil, err
 	}
-	log.Println("✓ Connected to Redis")
-	
+	logging.For("db").Info("connected to Redis")
+
 	return &DB{
 		Pool:  pool,
 		Redis: rdb,
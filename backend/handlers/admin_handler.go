@@ -0,0 +1,59 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/worlds-search/backend/backends"
+	"github.com/worlds-search/backend/indexer"
+	"github.com/worlds-search/backend/utils"
+)
+
+// AdminHandler handles operator-facing endpoints: the learned ranker
+// retrain trigger lives on SearchHandler since it already holds
+// LearningService; reindexing lives here since it only needs the active
+// SearchBackend and indexer.JobRegistry.
+type AdminHandler struct {
+	backend  backends.SearchBackend
+	registry *indexer.JobRegistry
+}
+
+// NewAdminHandler creates a new AdminHandler.
+func NewAdminHandler(backend backends.SearchBackend, registry *indexer.JobRegistry) *AdminHandler {
+	return &AdminHandler{backend: backend, registry: registry}
+}
+
+// ============================================================
+// POST /api/admin/reindex
+// ============================================================
+// Starts a bulk reindex of the worlds table into the active SearchBackend,
+// resuming from the last saved checkpoint if a previous run was
+// interrupted. Returns immediately with a job ID to poll.
+//
+// Response:
+//
+//	{ "jobId": "uuid" }
+func (h *AdminHandler) StartReindex(c *gin.Context) {
+	jobID := h.registry.Start(c.Request.Context(), h.backend)
+	c.JSON(http.StatusAccepted, gin.H{"jobId": jobID})
+}
+
+// ============================================================
+// GET /api/admin/reindex/:id
+// ============================================================
+// Returns the progress of a previously started reindex job.
+//
+// Response:
+//
+//	{ "processed": 12000, "failed": 3, "elapsedMs": 48213, "state": "running" }
+func (h *AdminHandler) GetReindexStatus(c *gin.Context) {
+	id := c.Param("id")
+
+	status, ok := h.registry.Get(id)
+	if !ok {
+		utils.RespondNotFound(c, "reindex job not found")
+		return
+	}
+
+	c.JSON(http.StatusOK, status)
+}
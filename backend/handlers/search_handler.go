@@ -1,11 +1,15 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
+	"strconv"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"github.com/worlds-search/backend/models"
+	"github.com/worlds-search/backend/searchparser"
 	"github.com/worlds-search/backend/services"
 	"github.com/worlds-search/backend/utils"
 )
@@ -15,6 +19,7 @@ type SearchHandler struct {
 	searchService      *services.SearchService
 	suggestionsService *services.SuggestionsService
 	compareService     *services.CompareService
+	learningService    *services.LearningService
 }
 
 // NewSearchHandler creates a new SearchHandler
@@ -22,11 +27,13 @@ func NewSearchHandler(
 	searchService *services.SearchService,
 	suggestionsService *services.SuggestionsService,
 	compareService *services.CompareService,
+	learningService *services.LearningService,
 ) *SearchHandler {
 	return &SearchHandler{
 		searchService:      searchService,
 		suggestionsService: suggestionsService,
 		compareService:     compareService,
+		learningService:    learningService,
 	}
 }
 
@@ -99,12 +106,18 @@ func (h *SearchHandler) GetSuggestions(c *gin.Context) {
 		}
 	}
 
-	response, err := h.suggestionsService.GetSuggestions(c.Request.Context(), keyword, userID)
+	fuzzyMode := models.ParseFuzzyMode(c.Query("fuzzy"))
+
+	response, partial, err := h.suggestionsService.GetSuggestions(c.Request.Context(), keyword, userID, fuzzyMode)
 	if err != nil {
 		utils.RespondInternalError(c, "Failed to get suggestions: "+err.Error())
 		return
 	}
 
+	if partial {
+		c.Header("X-Suggestions-Partial", "true")
+	}
+
 	c.JSON(http.StatusOK, response)
 }
 
@@ -154,6 +167,12 @@ func (h *SearchHandler) LogClick(c *gin.Context) {
 // Query params:
 //   - keyword (required): the search query
 //   - userId (optional): user ID for personalization
+//   - fuzzy (optional): "off" | "low" | "high" (default "high"), see
+//     models.FuzzyMode
+//   - algo (optional): "rrf" to rank with Reciprocal Rank Fusion (see
+//     WorldsRepository.SearchRRF) instead of the default SearchCombined
+//     match-type tiering; ignored when a non-pg_trgm SEARCH_BACKEND is
+//     configured
 //
 // Response:
 //
@@ -186,7 +205,34 @@ func (h *SearchHandler) GetResults(c *gin.Context) {
 		}
 	}
 
-	results, err := h.searchService.SearchWorlds(c.Request.Context(), keyword, userID)
+	fuzzyMode := models.ParseFuzzyMode(c.Query("fuzzy"))
+	useRRF := c.Query("algo") == "rrf"
+
+	results, err := h.searchService.SearchWorlds(c.Request.Context(), keyword, userID, fuzzyMode, useRRF)
+	if err != nil {
+		utils.RespondInternalError(c, "Failed to search worlds: "+err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, results)
+}
+
+// ============================================================
+// POST /api/search/results
+// ============================================================
+// Runs the structured search DSL: keyword plus filter clauses, a sort
+// spec, from/size pagination, and requested facets. This is the
+// deep-browsing counterpart to GET /api/search/results, which only
+// supports a bare keyword and a fixed top-N.
+// Request body: see models.SearchRequest.
+func (h *SearchHandler) PostResults(c *gin.Context) {
+	var req models.SearchRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.RespondBadRequest(c, "Invalid request body: "+err.Error())
+		return
+	}
+
+	results, err := h.searchService.SearchWorldsDSL(c.Request.Context(), req)
 	if err != nil {
 		utils.RespondInternalError(c, "Failed to search worlds: "+err.Error())
 		return
@@ -195,6 +241,96 @@ func (h *SearchHandler) GetResults(c *gin.Context) {
 	c.JSON(http.StatusOK, results)
 }
 
+// ============================================================
+// GET /api/search/query
+// ============================================================
+// Runs the structured query language (field filters, quoted phrases,
+// created:>date ranges, -negation) against the worlds table - the
+// richer counterpart to GET /api/search/results' bare keyword param.
+// Query params:
+//   - q (required): the structured query, e.g. `title:foo -bar created:>2024-01-01`
+func (h *SearchHandler) GetQueryResults(c *gin.Context) {
+	query := c.Query("q")
+	if query == "" {
+		c.JSON(http.StatusOK, services.SearchResults{
+			Keyword: "",
+			Worlds:  []models.WorldCard{},
+		})
+		return
+	}
+
+	const limit = 20
+	results, err := h.searchService.SearchWorldsQuery(c.Request.Context(), query, limit)
+	if err != nil {
+		var parseErr *searchparser.ParseError
+		if errors.As(err, &parseErr) {
+			utils.RespondBadRequest(c, err.Error())
+			return
+		}
+		utils.RespondInternalError(c, "Failed to search worlds: "+err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, results)
+}
+
+// ============================================================
+// GET /api/search/parse
+// ============================================================
+// Debug endpoint mirroring the compare-lab pattern: echoes the parsed AST
+// as JSON so the frontend (or a developer) can see exactly how the query
+// language tokenized a given string, including the offending token
+// position on a parse error.
+// Query params:
+//   - q (required): the query to parse
+func (h *SearchHandler) ParseQuery(c *gin.Context) {
+	query := c.Query("q")
+
+	node, err := searchparser.Parse(query)
+	if err != nil {
+		var parseErr *searchparser.ParseError
+		if errors.As(err, &parseErr) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":    err.Error(),
+				"token":    parseErr.Token,
+				"position": parseErr.Position,
+			})
+			return
+		}
+		utils.RespondInternalError(c, "Failed to parse query: "+err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"query": query, "ast": node})
+}
+
+// ============================================================
+// POST /api/admin/search/model/retrain
+// ============================================================
+// Refits the learned RankerModel from recent impressions/clicks and
+// persists it as the active model. Intended for manual/cron-triggered use
+// alongside StartPeriodicRetrain's schedule; safe to call concurrently
+// with normal traffic since RankingService reads the model through an
+// atomic pointer.
+//
+// Response:
+//
+//	{
+//	  "version": 4,
+//	  "prefixWeight": 98.2,
+//	  ...
+//	  "trainingRows": 12044
+//	}
+func (h *SearchHandler) RetrainModel(c *gin.Context) {
+	model, err := h.learningService.Retrain(c.Request.Context())
+	if err != nil {
+		utils.RespondInternalError(c, "Failed to retrain ranker model: "+err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, model)
+}
+
 // ============================================================
 // GET /api/search/trending
 // ============================================================
@@ -228,7 +364,16 @@ func (h *SearchHandler) GetTrending(c *gin.Context) {
 // This is for the Search Modes Lab page.
 // Query params:
 //   - keyword (required): the search query
-//   - mode (required): "trgm" | "bigram" | "both"
+//   - mode (required): "trgm" | "bigram" | "both" | "bleve" | "es" | "all" | "rrf"
+//   - fuzzy (optional): "off" | "low" | "high" (default "high"); only
+//     affects the trgm/bigram/both/rrf modes and those legs of mode=all,
+//     see models.FuzzyMode
+//   - k (optional): overrides the RRF damping constant (see
+//     WorldsRepository.SearchRRF); only affects mode=rrf and the rrf leg
+//     of mode=all
+//   - backends (optional): comma-separated subset of mode names (e.g.
+//     "trgm,bleve") to restrict mode=all's fan-out to; ignored by every
+//     other mode
 //
 // Response:
 //
@@ -273,13 +418,37 @@ func (h *SearchHandler) HandleSearchCompare(c *gin.Context) {
 		mode = services.SearchModeBigram
 	case "both":
 		mode = services.SearchModeBoth
+	case "bleve":
+		mode = services.SearchModeBleve
+	case "es":
+		mode = services.SearchModeES
+	case "all":
+		mode = services.SearchModeAll
+	case "rrf":
+		mode = services.SearchModeRRF
 	default:
-		utils.RespondBadRequest(c, "mode must be 'trgm', 'bigram', or 'both'")
+		utils.RespondBadRequest(c, "mode must be 'trgm', 'bigram', 'both', 'bleve', 'es', 'all', or 'rrf'")
 		return
 	}
 
+	fuzzyMode := models.ParseFuzzyMode(c.Query("fuzzy"))
+
+	var k float64
+	if kStr := c.Query("k"); kStr != "" {
+		if parsed, err := strconv.ParseFloat(kStr, 64); err == nil {
+			k = parsed
+		}
+	}
+
+	var backendModes []services.SearchMode
+	if backendsStr := c.Query("backends"); backendsStr != "" {
+		for _, name := range strings.Split(backendsStr, ",") {
+			backendModes = append(backendModes, services.SearchMode(strings.TrimSpace(name)))
+		}
+	}
+
 	const limit = 20
-	response, err := h.compareService.SearchCompare(c.Request.Context(), keyword, mode, limit)
+	response, err := h.compareService.SearchCompare(c.Request.Context(), keyword, mode, limit, fuzzyMode, k, backendModes)
 	if err != nil {
 		utils.RespondInternalError(c, "Failed to compare search: "+err.Error())
 		return
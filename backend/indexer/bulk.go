@@ -0,0 +1,185 @@
+package indexer
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/olivere/elastic/v7"
+	"github.com/worlds-search/backend/backends"
+	"github.com/worlds-search/backend/logging"
+	"github.com/worlds-search/backend/models"
+)
+
+// Default BulkIndexer tuning, modeled after olivere/elastic's bulk
+// processor defaults.
+const (
+	DefaultBulkSize      = 500
+	DefaultFlushInterval = 5 * time.Second
+	DefaultWorkers       = 4
+
+	initialBackoff = 100 * time.Millisecond
+	maxBackoff     = 30 * time.Second
+	maxRetries     = 6
+)
+
+// BulkIndexerConfig tunes a BulkIndexer.
+type BulkIndexerConfig struct {
+	BulkSize      int
+	FlushInterval time.Duration
+	Workers       int
+}
+
+// BulkIndexerStats is a point-in-time snapshot of progress, suitable for a
+// reindex job's status endpoint.
+type BulkIndexerStats struct {
+	Processed int64
+	Failed    int64
+}
+
+// BulkIndexer batches IndexWorld calls to a backends.SearchBackend across
+// a worker pool, retrying transient errors (429/503-class) with
+// exponential backoff and jitter, and dropping items on permanent 4xx
+// errors after logging - the same "retry transient, drop permanent"
+// split olivere/elastic's bulk processor makes.
+type BulkIndexer struct {
+	backend backends.SearchBackend
+	cfg     BulkIndexerConfig
+
+	mu     sync.Mutex
+	buffer []models.World
+	sem    chan struct{}
+
+	processed int64
+	failed    int64
+	statsMu   sync.Mutex
+}
+
+// NewBulkIndexer creates a BulkIndexer publishing to backend. Zero-value
+// fields in cfg fall back to the Default* constants.
+func NewBulkIndexer(backend backends.SearchBackend, cfg BulkIndexerConfig) *BulkIndexer {
+	if cfg.BulkSize <= 0 {
+		cfg.BulkSize = DefaultBulkSize
+	}
+	if cfg.FlushInterval <= 0 {
+		cfg.FlushInterval = DefaultFlushInterval
+	}
+	if cfg.Workers <= 0 {
+		cfg.Workers = DefaultWorkers
+	}
+
+	return &BulkIndexer{
+		backend: backend,
+		cfg:     cfg,
+		buffer:  make([]models.World, 0, cfg.BulkSize),
+		sem:     make(chan struct{}, cfg.Workers),
+	}
+}
+
+// Add buffers world for indexing, flushing synchronously once the buffer
+// reaches BulkSize. FlushInterval-based flushing is the caller's
+// responsibility since BulkIndexer has no background goroutine of its own
+// to keep its lifecycle tied to the caller's; ReindexJob does not tick it
+// and instead calls Flush once after its scan ends.
+func (bi *BulkIndexer) Add(ctx context.Context, world models.World) {
+	bi.mu.Lock()
+	bi.buffer = append(bi.buffer, world)
+	full := len(bi.buffer) >= bi.cfg.BulkSize
+	bi.mu.Unlock()
+
+	if full {
+		bi.Flush(ctx)
+	}
+}
+
+// Flush indexes every buffered world concurrently across Workers
+// goroutines and blocks until they all complete.
+func (bi *BulkIndexer) Flush(ctx context.Context) {
+	bi.mu.Lock()
+	batch := bi.buffer
+	bi.buffer = make([]models.World, 0, bi.cfg.BulkSize)
+	bi.mu.Unlock()
+
+	if len(batch) == 0 {
+		return
+	}
+
+	var wg sync.WaitGroup
+	for _, world := range batch {
+		world := world
+		bi.sem <- struct{}{}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-bi.sem }()
+			bi.indexWithRetry(ctx, world)
+		}()
+	}
+	wg.Wait()
+}
+
+// indexWithRetry indexes a single world, retrying transient (429/503-class)
+// errors with exponential backoff and full jitter up to maxRetries, and
+// dropping the item after logging on a permanent error.
+func (bi *BulkIndexer) indexWithRetry(ctx context.Context, world models.World) {
+	backoff := initialBackoff
+
+	for attempt := 0; ; attempt++ {
+		err := bi.backend.IndexWorld(ctx, world)
+		if err == nil {
+			bi.recordProcessed()
+			return
+		}
+
+		if !isRetryable(err) || attempt >= maxRetries {
+			logging.For("indexer").Warn("dropping world after exhausting retries", "worldId", world.ID, "attempts", attempt+1, "err", err)
+			bi.recordFailed()
+			return
+		}
+
+		sleep := time.Duration(rand.Int63n(int64(backoff)))
+		select {
+		case <-ctx.Done():
+			bi.recordFailed()
+			return
+		case <-time.After(sleep):
+		}
+
+		backoff *= 2
+		if backoff > maxBackoff {
+			backoff = maxBackoff
+		}
+	}
+}
+
+// isRetryable reports whether err looks like a transient 429 (rate
+// limited) or 503 (unavailable) response worth backing off and retrying,
+// as opposed to a permanent 4xx the backend will never accept. Backends
+// without HTTP status codes (Bleve, pg_trgm) are treated as non-retryable
+// since any error from them is almost certainly not transient.
+func isRetryable(err error) bool {
+	return elastic.IsStatusCode(err, 429) || elastic.IsStatusCode(err, 503) ||
+		errors.Is(err, context.DeadlineExceeded)
+}
+
+func (bi *BulkIndexer) recordProcessed() {
+	bi.statsMu.Lock()
+	bi.processed++
+	bi.statsMu.Unlock()
+}
+
+func (bi *BulkIndexer) recordFailed() {
+	bi.statsMu.Lock()
+	bi.failed++
+	bi.statsMu.Unlock()
+}
+
+// Stats returns a snapshot of how many worlds have been indexed or
+// dropped so far.
+func (bi *BulkIndexer) Stats() BulkIndexerStats {
+	bi.statsMu.Lock()
+	defer bi.statsMu.Unlock()
+	return BulkIndexerStats{Processed: bi.processed, Failed: bi.failed}
+}
@@ -0,0 +1,43 @@
+// Package indexer keeps a SearchBackend's index in sync with world writes.
+// It is deliberately a thin wrapper: callers that insert or update a world
+// push the change through an Indexer instead of talking to the backend
+// directly, so every write path (handlers today, the reindex subsystem
+// later) stays consistent.
+package indexer
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/worlds-search/backend/backends"
+	"github.com/worlds-search/backend/logging"
+	"github.com/worlds-search/backend/models"
+)
+
+// Indexer watches world mutations and pushes documents into the
+// configured SearchBackend. Indexing errors are logged rather than
+// propagated to the caller - a failed index update should not fail the
+// write that triggered it, since the backend can be reconciled later by a
+// bulk reindex.
+type Indexer struct {
+	backend backends.SearchBackend
+}
+
+// New creates an Indexer that publishes to backend.
+func New(backend backends.SearchBackend) *Indexer {
+	return &Indexer{backend: backend}
+}
+
+// OnWorldUpserted is called after a world row is inserted or updated.
+func (idx *Indexer) OnWorldUpserted(ctx context.Context, world models.World) {
+	if err := idx.backend.IndexWorld(ctx, world); err != nil {
+		logging.For("indexer").Warn("failed to index world", "worldId", world.ID, "backend", idx.backend.Name(), "err", err)
+	}
+}
+
+// OnWorldDeleted is called after a world row is deleted.
+func (idx *Indexer) OnWorldDeleted(ctx context.Context, id uuid.UUID) {
+	if err := idx.backend.DeleteWorld(ctx, id); err != nil {
+		logging.For("indexer").Warn("failed to delete world", "worldId", id, "backend", idx.backend.Name(), "err", err)
+	}
+}
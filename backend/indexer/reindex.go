@@ -0,0 +1,199 @@
+package indexer
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/worlds-search/backend/backends"
+	"github.com/worlds-search/backend/logging"
+	"github.com/worlds-search/backend/repositories"
+)
+
+// reindexBatchSize is how many worlds StreamWorldsBatch fetches per page
+// while walking the table - independent of BulkIndexer.BulkSize, which
+// governs how many worlds are buffered per flush (BulkIndexer.Workers
+// bounds how many IndexWorld calls run concurrently).
+const reindexBatchSize = 500
+
+// checkpointEvery is how many pages the resume token is persisted after,
+// trading a bit of re-work on crash for not hammering Postgres with a
+// write per page.
+const checkpointEvery = 4
+
+// JobState is the lifecycle of a reindex job.
+type JobState string
+
+const (
+	JobRunning   JobState = "running"
+	JobCompleted JobState = "completed"
+	JobFailed    JobState = "failed"
+)
+
+// JobStatus is the point-in-time snapshot GET /api/admin/reindex/:id
+// returns.
+type JobStatus struct {
+	Processed int64    `json:"processed"`
+	Failed    int64    `json:"failed"`
+	ElapsedMs int64    `json:"elapsedMs"`
+	State     JobState `json:"state"`
+	Error     string   `json:"error,omitempty"`
+}
+
+// ReindexJob streams every row of the worlds table into a SearchBackend in
+// batches via a BulkIndexer, checkpointing a resume token along the way so
+// an interrupted run picks up where it stopped instead of rescanning the
+// whole table.
+type ReindexJob struct {
+	worldsRepo *repositories.WorldsRepository
+	stateRepo  *repositories.ReindexStateRepository
+	bulk       *BulkIndexer
+	startedAt  time.Time
+
+	mu    sync.RWMutex
+	state JobState
+	err   error
+}
+
+// newReindexJob creates a job targeting backend. Unexported - jobs are
+// only created (and tracked) through JobRegistry.Start.
+func newReindexJob(backend backends.SearchBackend, worldsRepo *repositories.WorldsRepository, stateRepo *repositories.ReindexStateRepository) *ReindexJob {
+	return &ReindexJob{
+		worldsRepo: worldsRepo,
+		stateRepo:  stateRepo,
+		bulk:       NewBulkIndexer(backend, BulkIndexerConfig{}),
+		startedAt:  time.Now(),
+		state:      JobRunning,
+	}
+}
+
+// run walks the worlds table from the last saved resume token (if any),
+// bulk-indexing every page and checkpointing progress as it goes. It is
+// meant to run in its own goroutine; JobRegistry.Start spawns it.
+func (j *ReindexJob) run(ctx context.Context) {
+	token, err := j.stateRepo.GetResumeToken(ctx)
+	if err != nil {
+		logging.For("indexer").Warn("failed to load resume token, starting from the beginning", "err", err)
+		token = nil
+	}
+
+	pages := 0
+	for {
+		batch, err := j.worldsRepo.StreamWorldsBatch(ctx, token, reindexBatchSize)
+		if err != nil {
+			j.fail(err)
+			return
+		}
+		if len(batch) == 0 {
+			break
+		}
+
+		for _, world := range batch {
+			j.bulk.Add(ctx, world)
+		}
+		last := batch[len(batch)-1]
+		token = &repositories.ResumeToken{LastCreatedAt: last.CreatedAt, LastID: last.ID}
+
+		pages++
+		if pages%checkpointEvery == 0 {
+			if err := j.stateRepo.SaveResumeToken(ctx, *token); err != nil {
+				logging.For("indexer").Warn("failed to checkpoint resume token", "err", err)
+			}
+		}
+	}
+
+	j.bulk.Flush(ctx)
+
+	if token != nil {
+		if err := j.stateRepo.SaveResumeToken(ctx, *token); err != nil {
+			logging.For("indexer").Warn("failed to save final resume token", "err", err)
+		}
+	}
+	// The scan reached the end of the table - clear the cursor so the next
+	// run does a fresh full pass instead of finding nothing after it.
+	if err := j.stateRepo.ClearResumeToken(ctx); err != nil {
+		logging.For("indexer").Warn("failed to clear resume token after completion", "err", err)
+	}
+
+	j.mu.Lock()
+	j.state = JobCompleted
+	j.mu.Unlock()
+}
+
+func (j *ReindexJob) fail(err error) {
+	j.mu.Lock()
+	j.state = JobFailed
+	j.err = err
+	j.mu.Unlock()
+	logging.For("indexer").Warn("job failed", "err", err)
+}
+
+// Status returns a point-in-time snapshot of the job's progress.
+func (j *ReindexJob) Status() JobStatus {
+	j.mu.RLock()
+	state, err := j.state, j.err
+	j.mu.RUnlock()
+
+	stats := j.bulk.Stats()
+	status := JobStatus{
+		Processed: stats.Processed,
+		Failed:    stats.Failed,
+		ElapsedMs: time.Since(j.startedAt).Milliseconds(),
+		State:     state,
+	}
+	if err != nil {
+		status.Error = err.Error()
+	}
+	return status
+}
+
+// JobRegistry is a small in-memory registry of reindex jobs, keyed by job
+// ID. It does not persist across restarts - only the resume token in
+// Postgres does, so a restarted process can still continue a previous
+// run's progress even though its job ID is gone.
+type JobRegistry struct {
+	worldsRepo *repositories.WorldsRepository
+	stateRepo  *repositories.ReindexStateRepository
+
+	mu   sync.RWMutex
+	jobs map[string]*ReindexJob
+}
+
+// NewJobRegistry creates a new JobRegistry.
+func NewJobRegistry(worldsRepo *repositories.WorldsRepository, stateRepo *repositories.ReindexStateRepository) *JobRegistry {
+	return &JobRegistry{
+		worldsRepo: worldsRepo,
+		stateRepo:  stateRepo,
+		jobs:       make(map[string]*ReindexJob),
+	}
+}
+
+// Start creates a new reindex job against backend and runs it in the
+// background, returning its job ID immediately.
+func (reg *JobRegistry) Start(ctx context.Context, backend backends.SearchBackend) string {
+	job := newReindexJob(backend, reg.worldsRepo, reg.stateRepo)
+	id := uuid.NewString()
+
+	reg.mu.Lock()
+	reg.jobs[id] = job
+	reg.mu.Unlock()
+
+	// Detached from the request context - a reindex should outlive the
+	// HTTP request that kicked it off, same reasoning as
+	// LearningService.StartPeriodicRetrain's background ticker.
+	go job.run(context.Background())
+
+	return id
+}
+
+// Get returns the status of job id, or false if no such job exists.
+func (reg *JobRegistry) Get(id string) (JobStatus, bool) {
+	reg.mu.RLock()
+	job, ok := reg.jobs[id]
+	reg.mu.RUnlock()
+	if !ok {
+		return JobStatus{}, false
+	}
+	return job.Status(), true
+}
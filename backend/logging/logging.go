@@ -0,0 +1,58 @@
+// Package logging provides structured logging (log/slog, JSON to stdout)
+// with independently configurable levels per subsystem - e.g. LOG_LEVEL_DB
+// can turn on every SQL query at debug without also enabling debug
+// logging for the HTTP or ranking subsystems. It's introduced starting
+// with the "db" subsystem (see QueryTracer); the rest of the codebase's
+// log.Printf calls migrate over subsystem by subsystem rather than in one
+// sweep.
+package logging
+
+import (
+	"log/slog"
+	"os"
+	"strings"
+)
+
+// defaultLevel is the fallback for any subsystem without its own
+// LOG_LEVEL_<SUBSYSTEM> override. Set by Init; info until then, same as
+// config.Config.LogLevel's own default.
+var defaultLevel = slog.LevelInfo
+
+// Init sets the default log level from cfg.LogLevel. Call once at
+// startup, before the first For call that should observe it - mirrors
+// telemetry.Init's "config owns the env var, the package takes the
+// resolved value" shape rather than reading LOG_LEVEL directly here.
+func Init(level string) {
+	defaultLevel = parseLevel(level, slog.LevelInfo)
+}
+
+// For returns a JSON-handler logger scoped to subsystem, tagged with a
+// "subsystem" attribute and filtered at the level LOG_LEVEL_<SUBSYSTEM>
+// requests (falling back to the level Init was given, then info).
+func For(subsystem string) *slog.Logger {
+	h := slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{Level: subsystemLevel(subsystem)})
+	return slog.New(h).With("subsystem", subsystem)
+}
+
+func subsystemLevel(subsystem string) slog.Level {
+	key := "LOG_LEVEL_" + strings.ToUpper(subsystem)
+	if v := os.Getenv(key); v != "" {
+		return parseLevel(v, defaultLevel)
+	}
+	return defaultLevel
+}
+
+func parseLevel(s string, fallback slog.Level) slog.Level {
+	switch strings.ToLower(s) {
+	case "debug":
+		return slog.LevelDebug
+	case "info":
+		return slog.LevelInfo
+	case "warn", "warning":
+		return slog.LevelWarn
+	case "error":
+		return slog.LevelError
+	default:
+		return fallback
+	}
+}
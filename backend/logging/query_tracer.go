@@ -0,0 +1,47 @@
+package logging
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// QueryTracer implements pgx.QueryTracer, logging every SQL statement
+// under the "db" subsystem - set LOG_LEVEL_DB=debug to see query text and
+// timing without enabling debug logging everywhere else. Wired into
+// db.New via pgxpool.Config.ConnConfig.Tracer.
+type QueryTracer struct {
+	logger *slog.Logger
+}
+
+// NewQueryTracer creates a QueryTracer logging to the "db" subsystem.
+func NewQueryTracer() *QueryTracer {
+	return &QueryTracer{logger: For("db")}
+}
+
+type queryStartTimeKey struct{}
+
+// TraceQueryStart logs the SQL text and args at debug and stashes a start
+// time in ctx for TraceQueryEnd to compute elapsed duration from.
+func (t *QueryTracer) TraceQueryStart(ctx context.Context, conn *pgx.Conn, data pgx.TraceQueryStartData) context.Context {
+	t.logger.Debug("query start", "sql", data.SQL, "args", data.Args)
+	return context.WithValue(ctx, queryStartTimeKey{}, time.Now())
+}
+
+// TraceQueryEnd logs the outcome of the query started by TraceQueryStart -
+// errors at error level (SQL queries failing is worth seeing regardless of
+// LOG_LEVEL_DB), successful completions at debug.
+func (t *QueryTracer) TraceQueryEnd(ctx context.Context, conn *pgx.Conn, data pgx.TraceQueryEndData) {
+	var elapsed time.Duration
+	if start, ok := ctx.Value(queryStartTimeKey{}).(time.Time); ok {
+		elapsed = time.Since(start)
+	}
+
+	if data.Err != nil {
+		t.logger.Error("query failed", "err", data.Err, "elapsedMs", elapsed.Milliseconds())
+		return
+	}
+	t.logger.Debug("query end", "commandTag", data.CommandTag.String(), "elapsedMs", elapsed.Milliseconds())
+}
@@ -2,36 +2,50 @@ package main
 
 import (
 	"context"
-	"log"
 	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
 	"time"
 
+	"github.com/worlds-search/backend/backends"
 	"github.com/worlds-search/backend/config"
 	"github.com/worlds-search/backend/db"
 	"github.com/worlds-search/backend/handlers"
+	"github.com/worlds-search/backend/indexer"
+	"github.com/worlds-search/backend/logging"
 	"github.com/worlds-search/backend/repositories"
 	"github.com/worlds-search/backend/router"
 	"github.com/worlds-search/backend/services"
+	"github.com/worlds-search/backend/telemetry"
 )
 
 func main() {
-	log.Println("🚀 Starting Worlds Search Backend...")
-
 	// Load configuration
 	cfg := config.Load()
-	log.Printf("📋 Config loaded: Port=%s", cfg.ServerPort)
+	logging.Init(cfg.LogLevel)
+	logger := logging.For("main")
+
+	logger.Info("starting worlds search backend")
+	logger.Info("config loaded", "port", cfg.ServerPort)
 
 	// Create context for graceful shutdown
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
+	// Metrics are always exposed at /metrics; tracing only exports
+	// anywhere if TRACE_EXPORTER is set.
+	telemetryShutdown, err := telemetry.Init(ctx, cfg.TraceExporter, "worlds-search-backend")
+	if err != nil {
+		logger.Error("failed to initialize telemetry", "err", err)
+		os.Exit(1)
+	}
+
 	// Connect to databases
 	database, err := db.New(ctx, cfg.PostgresDSN, cfg.RedisAddr)
 	if err != nil {
-		log.Fatalf("❌ Failed to connect to databases: %v", err)
+		logger.Error("failed to connect to databases", "err", err)
+		os.Exit(1)
 	}
 	defer database.Close()
 
@@ -40,18 +54,102 @@ func main() {
 	searchRepo := repositories.NewSearchRepository(database.Pool)
 	logsRepo := repositories.NewLogsRepository(database.Pool, database.Redis)
 
+	// One-time backfill of the sharded trending prefix index from the
+	// legacy flat trending_search ZSET, then keep the decay epoch from
+	// overflowing float64 over long uptimes.
+	if err := logsRepo.MigrateLegacyTrending(ctx); err != nil {
+		logger.Warn("failed to migrate legacy trending data", "err", err)
+	}
+	logsRepo.StartTrendingEpochRenormalizer(ctx, 12*time.Hour)
+
+	// Build the configured full-text search backend (SEARCH_BACKEND env var)
+	searchBackend, err := backends.New(ctx, backends.Options{
+		Kind:        cfg.SearchBackend,
+		BlevePath:   cfg.BlevePath,
+		ElasticURL:  cfg.ElasticURL,
+		MeiliHost:   cfg.MeiliHost,
+		MeiliAPIKey: cfg.MeiliAPIKey,
+	}, worldsRepo)
+	if err != nil {
+		logger.Error("failed to initialize search backend", "searchBackend", cfg.SearchBackend, "err", err)
+		os.Exit(1)
+	}
+	logger.Info("search backend ready", "backend", searchBackend.Name())
+	worldIndexer := indexer.New(searchBackend)
+	_ = worldIndexer // wired in once world writes exist
+
+	// Bulk reindex subsystem: streams the whole worlds table into
+	// searchBackend in batches, resuming from reindex_state if a previous
+	// run was interrupted. Jobs are tracked in-memory only - the resume
+	// token in Postgres is what survives a restart.
+	reindexStateRepo := repositories.NewReindexStateRepository(database.Pool)
+	reindexJobs := indexer.NewJobRegistry(worldsRepo, reindexStateRepo)
+
+	// The Search Modes Lab can additionally compare against Bleve and
+	// Elasticsearch regardless of which backend is primary. Both are
+	// best-effort: if they fail to initialize (e.g. no ES cluster
+	// reachable), the corresponding lab mode is simply unavailable.
+	var compareBleve backends.SearchBackend
+	if b, err := backends.NewBleveBackend(cfg.BlevePath); err != nil {
+		logger.Warn("bleve compare backend unavailable", "err", err)
+	} else {
+		compareBleve = b
+	}
+	var compareElastic backends.SearchBackend
+	if b, err := backends.NewElasticBackend(ctx, cfg.ElasticURL); err != nil {
+		logger.Warn("elasticsearch compare backend unavailable", "err", err)
+	} else {
+		compareElastic = b
+	}
+
+	// LogQueueBackend picks how impressions/clicks leave the request path.
+	// Default "inprocess": impressions flow through a bounded sink that
+	// coalesces records and flushes them in batches (dropping records
+	// once its queue is full under sustained overload), clicks write to Postgres
+	// directly. "redis" buffers both through RedisLogQueue instead, for
+	// cmd/logs-runner to drain in a separate process.
+	var impressionQueue repositories.ImpressionQueue
+	var clickQueue repositories.ClickQueue
+	var impressionSink *repositories.ImpressionSink // non-nil only for LogQueueBackend "inprocess", so it can be drained on shutdown
+	switch cfg.LogQueueBackend {
+	case "redis":
+		redisLogQueue := repositories.NewRedisLogQueue(database.Redis)
+		impressionQueue = redisLogQueue
+		clickQueue = redisLogQueue
+		logger.Info("log queue backend: redis (drained by cmd/logs-runner)")
+	default:
+		impressionSink = repositories.NewImpressionSink(
+			logsRepo,
+			repositories.DefaultSinkWorkers,
+			repositories.DefaultSinkQueueSize,
+			repositories.DefaultSinkBatchSize,
+			repositories.DefaultSinkFlushInterval,
+		)
+		impressionQueue = impressionSink
+		logger.Info("log queue backend: in-process (ImpressionSink + synchronous click inserts)")
+	}
+
+	// The learned ranker reloads its weights from Postgres on startup and
+	// refits them periodically from implicit click feedback; RankingService
+	// falls back to the static hand-tuned formula for traffic outside its
+	// A/B bucket (or entirely, if learningService is nil).
+	rankerModelRepo := repositories.NewRankerModelRepository(database.Pool)
+	learningService := services.NewLearningService(ctx, logsRepo, rankerModelRepo)
+	learningService.StartPeriodicRetrain(ctx, 30*time.Minute)
+
 	// Initialize services
-	rankingService := services.NewRankingService(searchRepo, logsRepo)
-	suggestionsService := services.NewSuggestionsService(worldsRepo, searchRepo, logsRepo, rankingService)
-	searchService := services.NewSearchService(worldsRepo, searchRepo, logsRepo)
-	compareService := services.NewCompareService(worldsRepo)
+	rankingService := services.NewRankingService(searchRepo, logsRepo, learningService)
+	suggestionsService := services.NewSuggestionsService(worldsRepo, searchRepo, logsRepo, rankingService, impressionQueue)
+	searchService := services.NewSearchService(worldsRepo, searchRepo, logsRepo, searchBackend, clickQueue)
+	compareService := services.NewCompareService(worldsRepo, compareBleve, compareElastic)
 
 	// Initialize handlers
-	searchHandler := handlers.NewSearchHandler(searchService, suggestionsService, compareService)
+	searchHandler := handlers.NewSearchHandler(searchService, suggestionsService, compareService, learningService)
 	worldsHandler := handlers.NewWorldsHandler(searchService)
+	adminHandler := handlers.NewAdminHandler(searchBackend, reindexJobs)
 
 	// Create router
-	r := router.New(searchHandler, worldsHandler)
+	r := router.New(searchHandler, worldsHandler, adminHandler)
 
 	// Create HTTP server
 	srv := &http.Server{
@@ -64,9 +162,10 @@ func main() {
 
 	// Start server in goroutine
 	go func() {
-		log.Printf("✅ Server listening on http://0.0.0.0:%s", cfg.ServerPort)
+		logger.Info("server listening", "addr", "http://0.0.0.0:"+cfg.ServerPort)
 		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-			log.Fatalf("❌ Server error: %v", err)
+			logger.Error("server error", "err", err)
+			os.Exit(1)
 		}
 	}()
 
@@ -75,15 +174,28 @@ func main() {
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 	<-quit
 
-	log.Println("🛑 Shutting down server...")
+	logger.Info("shutting down server")
 
 	// Give outstanding requests 5 seconds to complete
 	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer shutdownCancel()
 
 	if err := srv.Shutdown(shutdownCtx); err != nil {
-		log.Printf("❌ Server forced to shutdown: %v", err)
+		logger.Error("server forced to shutdown", "err", err)
+	}
+
+	// Drain whatever impressions are still queued before the database
+	// connections underneath them go away. Only the in-process sink has
+	// anything to drain - RedisLogQueue pushes are already durable in Redis.
+	if impressionSink != nil {
+		if err := impressionSink.Close(shutdownCtx); err != nil {
+			logger.Warn("impression sink did not drain cleanly", "err", err)
+		}
+	}
+
+	if err := telemetryShutdown(shutdownCtx); err != nil {
+		logger.Warn("telemetry did not flush cleanly", "err", err)
 	}
 
-	log.Println("👋 Server exited")
+	logger.Info("server exited")
 }
@@ -0,0 +1,50 @@
+// Package middleware holds Gin middleware shared across the router.
+package middleware
+
+import (
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/worlds-search/backend/logging"
+	"github.com/worlds-search/backend/utils"
+)
+
+// requestIDHeader is both read (to let an upstream proxy supply its own
+// correlation ID) and written (so the caller can see the ID we logged
+// under) on every request.
+const requestIDHeader = "X-Request-Id"
+
+// RequestLogger logs method, path, status, latency, and request ID as
+// structured fields under the "http" subsystem (LOG_LEVEL_HTTP, see the
+// logging package) for every request. When the request carries a
+// "keyword" query param (the common case across /api/search/*), its
+// normalized form is logged too, since that's the field most search
+// issues get debugged from.
+func RequestLogger() gin.HandlerFunc {
+	logger := logging.For("http")
+
+	return func(c *gin.Context) {
+		requestID := c.GetHeader(requestIDHeader)
+		if requestID == "" {
+			requestID = uuid.NewString()
+		}
+		c.Writer.Header().Set(requestIDHeader, requestID)
+
+		start := time.Now()
+		c.Next()
+
+		fields := []any{
+			"method", c.Request.Method,
+			"path", c.Request.URL.Path,
+			"status", c.Writer.Status(),
+			"latencyMs", time.Since(start).Milliseconds(),
+			"requestId", requestID,
+		}
+		if keyword := c.Query("keyword"); keyword != "" {
+			fields = append(fields, "normalizedKeyword", utils.NormalizeKeyword(keyword))
+		}
+
+		logger.Info("request", fields...)
+	}
+}
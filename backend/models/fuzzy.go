@@ -0,0 +1,27 @@
+package models
+
+import "strings"
+
+// FuzzyMode controls how much typo-tolerance a search applies, from the
+// `fuzzy` query param accepted by /api/search/results and
+// /api/search/suggestions.
+type FuzzyMode string
+
+const (
+	FuzzyOff  FuzzyMode = "off"  // exact-match only
+	FuzzyLow  FuzzyMode = "low"  // raised similarity threshold
+	FuzzyHigh FuzzyMode = "high" // default recall-favoring threshold
+)
+
+// ParseFuzzyMode parses the `fuzzy` query param, defaulting to FuzzyHigh
+// (today's behavior) for an empty or unrecognized value.
+func ParseFuzzyMode(raw string) FuzzyMode {
+	switch FuzzyMode(strings.ToLower(raw)) {
+	case FuzzyOff:
+		return FuzzyOff
+	case FuzzyLow:
+		return FuzzyLow
+	default:
+		return FuzzyHigh
+	}
+}
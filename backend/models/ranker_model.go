@@ -0,0 +1,58 @@
+package models
+
+import "time"
+
+// RankerFeatures is the feature vector the learned ranker scores, and the
+// same shape LearningService builds training examples from. Each feature
+// is expected to already be normalized roughly onto a comparable scale
+// (e.g. 0-1 or a handful of units) so a single weight per feature is
+// meaningful.
+type RankerFeatures struct {
+	Prefix       float64 // 1.0 prefix match, 0.5 contains match, else 0
+	PersonalFreq float64 // user's search frequency for this candidate
+	TrendingZ    float64 // trending score, same units as the decayed ZSET score
+	FuzzySim     float64 // pg_trgm similarity (0-1)
+	CTRSmoothed  float64 // Wilson-smoothed CTR (0-1)
+	PositionBias float64 // 1/log2(2+pos) examination probability at the position this candidate was shown
+}
+
+// RankerModel holds the per-feature weights the learned ranker applies to
+// a RankerFeatures vector. The zero value is meaningless - use
+// DefaultRankerModel for the weights that reproduce the original
+// hand-tuned RankingService formula.
+type RankerModel struct {
+	Version        int       `json:"version"`
+	PrefixWeight   float64   `json:"prefixWeight"`
+	PersonalWeight float64   `json:"personalWeight"`
+	TrendingWeight float64   `json:"trendingWeight"`
+	FuzzyWeight    float64   `json:"fuzzyWeight"`
+	CTRWeight      float64   `json:"ctrWeight"`
+	TrainedAt      time.Time `json:"trainedAt"`
+	TrainingRows   int       `json:"trainingRows"`
+}
+
+// DefaultRankerModel returns the weights matching the original hard-coded
+// formula in RankingService.computeScores, so a freshly deployed instance
+// with no trained model yet behaves exactly like before learning-to-rank
+// was introduced.
+func DefaultRankerModel() RankerModel {
+	return RankerModel{
+		Version:        0,
+		PrefixWeight:   100.0,
+		PersonalWeight: 20.0,
+		TrendingWeight: 1.0,
+		FuzzyWeight:    10.0,
+		CTRWeight:      50.0,
+	}
+}
+
+// Score blends the feature vector with the model's weights into a single
+// ranking score, the learned-ranker counterpart of the static formula in
+// RankingService.computeScores.
+func (m RankerModel) Score(f RankerFeatures) float64 {
+	return f.Prefix*m.PrefixWeight +
+		f.PersonalFreq*m.PersonalWeight +
+		f.TrendingZ*m.TrendingWeight +
+		f.FuzzySim*m.FuzzyWeight +
+		f.CTRSmoothed*m.CTRWeight
+}
@@ -0,0 +1,74 @@
+package models
+
+// SortMode selects how SearchRequest results are ordered.
+type SortMode string
+
+const (
+	SortRelevance SortMode = "relevance"
+	SortRecency   SortMode = "recency"
+	SortPopularity SortMode = "popularity"
+)
+
+// FilterField identifies which world attribute a FilterClause narrows on.
+// Tag, Owner, and WorldType are accepted by the request shape but are
+// currently no-ops: the worlds table has no tag/owner/world_type columns
+// yet, so the repository silently ignores clauses on those fields rather
+// than erroring. CreatedAt is the one filter that's actually wired up.
+type FilterField string
+
+const (
+	FilterTag       FilterField = "tag"
+	FilterOwner     FilterField = "owner"
+	FilterCreatedAt FilterField = "created_at"
+	FilterWorldType FilterField = "world_type"
+)
+
+// FilterOp is a comparison operator for a FilterClause.
+type FilterOp string
+
+const (
+	OpEquals      FilterOp = "eq"
+	OpGreaterThan FilterOp = "gt"
+	OpLessThan    FilterOp = "lt"
+)
+
+// FilterClause is a single structured filter, e.g. {Field: "created_at",
+// Op: "gt", Value: "2024-01-01T00:00:00Z"}.
+type FilterClause struct {
+	Field FilterField `json:"field" binding:"required"`
+	Op    FilterOp    `json:"op" binding:"required"`
+	Value string      `json:"value" binding:"required"`
+}
+
+// SearchRequest is the structured request body for POST /api/search/results.
+type SearchRequest struct {
+	Keyword string         `json:"keyword"`
+	Filters []FilterClause `json:"filters"`
+	Sort    SortMode       `json:"sort"`
+	From    int            `json:"from"`
+	Size    int            `json:"size"`
+	Facets  []string       `json:"facets"`
+}
+
+// SearchHit is a single result row with per-field highlight snippets.
+type SearchHit struct {
+	World      WorldCard           `json:"world"`
+	Highlights map[string][]string `json:"highlights,omitempty"`
+}
+
+// FacetBucket is one value/count pair within a facet.
+type FacetBucket struct {
+	Value string `json:"value"`
+	Count int    `json:"count"`
+}
+
+// SearchResultsV2 is the response for the structured search DSL.
+type SearchResultsV2 struct {
+	Keyword   string                   `json:"keyword"`
+	Hits      []SearchHit              `json:"hits"`
+	Total     int                      `json:"total"`
+	Facets    map[string][]FacetBucket `json:"facets"`
+	From      int                      `json:"from"`
+	Size      int                      `json:"size"`
+	ElapsedMs int64                    `json:"elapsedMs"`
+}
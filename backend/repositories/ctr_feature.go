@@ -0,0 +1,170 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+)
+
+const (
+	// ctrCacheTTL is how long a smoothed CTR value is cached in Redis
+	// before GetSmoothedCTRBatch will recompute it from Postgres.
+	ctrCacheTTL = 5 * time.Minute
+
+	// globalPriorCTR is the smoothed CTR assumed for a suggestion with no
+	// impressions in the lookback window.
+	globalPriorCTR = 0.05
+
+	// wilsonZ is the z-score for a 95% confidence interval.
+	wilsonZ = 1.96
+)
+
+// SuggestionKey identifies a (suggestion, suggestion_type) pair for
+// batched CTR lookups.
+type SuggestionKey struct {
+	Suggestion     string
+	SuggestionType string
+}
+
+func ctrCacheKey(key SuggestionKey) string {
+	return "ctr:" + key.SuggestionType + ":" + key.Suggestion
+}
+
+// wilsonLowerBound computes the lower bound of the Wilson score interval
+// at the given z confidence for clicks out of impressions. This is a more
+// conservative (and less noisy for small n) estimate of true CTR than the
+// raw clicks/impressions ratio.
+func wilsonLowerBound(clicks, impressions int, z float64) float64 {
+	if impressions == 0 {
+		return 0
+	}
+	n := float64(impressions)
+	p := float64(clicks) / n
+	return (p + z*z/(2*n) - z*math.Sqrt((p*(1-p)+z*z/(4*n))/n)) / (1 + z*z/n)
+}
+
+// GetCTRStatsBatch fetches 7-day (impressions, clicks) for every key in a
+// single round-trip per table, instead of the two-query-per-candidate
+// pattern GetCTRStats uses. Keys with no rows come back with a zeroed
+// CTRStats rather than being omitted.
+func (r *LogsRepository) GetCTRStatsBatch(ctx context.Context, keys []SuggestionKey) (map[SuggestionKey]CTRStats, error) {
+	stats := make(map[SuggestionKey]CTRStats, len(keys))
+	for _, k := range keys {
+		stats[k] = CTRStats{}
+	}
+	if len(keys) == 0 {
+		return stats, nil
+	}
+
+	suggestions := make([]string, len(keys))
+	types := make([]string, len(keys))
+	for i, k := range keys {
+		suggestions[i] = k.Suggestion
+		types[i] = k.SuggestionType
+	}
+
+	impressionQuery := `
+		SELECT suggestion, suggestion_type, COUNT(*)
+		FROM search_impressions
+		WHERE (suggestion, suggestion_type) IN (SELECT unnest($1::text[]), unnest($2::text[]))
+		  AND created_at > NOW() - INTERVAL '7 days'
+		GROUP BY suggestion, suggestion_type
+	`
+	rows, err := r.pool.Query(ctx, impressionQuery, suggestions, types)
+	if err != nil {
+		return nil, err
+	}
+	for rows.Next() {
+		var k SuggestionKey
+		var count int
+		if err := rows.Scan(&k.Suggestion, &k.SuggestionType, &count); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		s := stats[k]
+		s.Impressions = count
+		stats[k] = s
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	clickQuery := `
+		SELECT clicked_suggestion, suggestion_type, COUNT(*)
+		FROM search_clicks
+		WHERE (clicked_suggestion, suggestion_type) IN (SELECT unnest($1::text[]), unnest($2::text[]))
+		  AND created_at > NOW() - INTERVAL '7 days'
+		GROUP BY clicked_suggestion, suggestion_type
+	`
+	rows, err = r.pool.Query(ctx, clickQuery, suggestions, types)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var k SuggestionKey
+		var count int
+		if err := rows.Scan(&k.Suggestion, &k.SuggestionType, &count); err != nil {
+			return nil, err
+		}
+		s := stats[k]
+		s.Clicks = count
+		stats[k] = s
+	}
+
+	return stats, rows.Err()
+}
+
+// GetSmoothedCTRBatch returns the Wilson-score-smoothed CTR for each key,
+// reading through a 5-minute Redis cache and only hitting Postgres for
+// keys that missed. Keys with zero impressions fall back to
+// globalPriorCTR.
+func (r *LogsRepository) GetSmoothedCTRBatch(ctx context.Context, keys []SuggestionKey) (map[SuggestionKey]float64, error) {
+	result := make(map[SuggestionKey]float64, len(keys))
+	if len(keys) == 0 {
+		return result, nil
+	}
+
+	var misses []SuggestionKey
+	for _, k := range keys {
+		val, err := r.redis.Get(ctx, ctrCacheKey(k)).Result()
+		if err != nil {
+			misses = append(misses, k)
+			continue
+		}
+		parsed, err := strconv.ParseFloat(val, 64)
+		if err != nil {
+			misses = append(misses, k)
+			continue
+		}
+		result[k] = parsed
+	}
+
+	if len(misses) == 0 {
+		return result, nil
+	}
+
+	stats, err := r.GetCTRStatsBatch(ctx, misses)
+	if err != nil {
+		return nil, fmt.Errorf("repositories: batch CTR lookup: %w", err)
+	}
+
+	pipe := r.redis.Pipeline()
+	for _, k := range misses {
+		s := stats[k]
+		smoothed := globalPriorCTR
+		if s.Impressions > 0 {
+			smoothed = wilsonLowerBound(s.Clicks, s.Impressions, wilsonZ)
+		}
+		result[k] = smoothed
+		pipe.Set(ctx, ctrCacheKey(k), strconv.FormatFloat(smoothed, 'f', -1, 64), ctrCacheTTL)
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
@@ -0,0 +1,46 @@
+package repositories
+
+import (
+	"strings"
+
+	"github.com/worlds-search/backend/models"
+)
+
+// fuzzyThreshold maps a fuzzy mode to the pg_trgm similarity() threshold a
+// query should use. FuzzyOff still needs *some* threshold to bound the
+// candidate set pg_trgm returns - exactTokenFilter below is what actually
+// enforces "no fuzzy matches" for that mode.
+func fuzzyThreshold(mode models.FuzzyMode) float64 {
+	switch mode {
+	case models.FuzzyLow, models.FuzzyOff:
+		return 0.5
+	default: // models.FuzzyHigh and unset
+		return 0.1
+	}
+}
+
+// exactTokenFilter drops results whose title doesn't literally contain at
+// least one whitespace-separated token of the normalized keyword. pg_trgm
+// has no per-query way to fully disable fuzziness short of dropping the
+// index, so when fuzzy=off this Go-side pass is what actually enforces
+// "exact match only" on top of whatever pg_trgm/pg_bigm returned - the
+// same workaround pattern used for a backend with no per-query fuzziness
+// knob at all.
+func exactTokenFilter(results []WorldWithSimilarity, normalizedKeyword string) []WorldWithSimilarity {
+	tokens := strings.Fields(normalizedKeyword)
+	if len(tokens) == 0 {
+		return results
+	}
+
+	filtered := make([]WorldWithSimilarity, 0, len(results))
+	for _, ws := range results {
+		title := strings.ToLower(ws.World.Title)
+		for _, tok := range tokens {
+			if strings.Contains(title, tok) {
+				filtered = append(filtered, ws)
+				break
+			}
+		}
+	}
+	return filtered
+}
@@ -0,0 +1,156 @@
+package repositories
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/worlds-search/backend/logging"
+)
+
+const (
+	DefaultSinkWorkers       = 4
+	DefaultSinkQueueSize     = 2000
+	DefaultSinkBatchSize     = 200
+	DefaultSinkFlushInterval = 250 * time.Millisecond
+)
+
+// ImpressionSink coalesces impression records from many concurrent
+// GetSuggestions calls into bounded batches before flushing them through
+// LogsRepository.InsertImpressionsBatch. This replaces spawning one
+// goroutine with context.Background() per request, which let a traffic
+// spike open unbounded connections to Postgres.
+//
+// Enqueue never blocks: once the queue is full, records are dropped and
+// counted rather than applying backpressure to the request path, since an
+// impression log is best-effort telemetry, not something a caller should
+// wait on.
+type ImpressionSink struct {
+	repo          *LogsRepository
+	queue         chan ImpressionRecord
+	batchSize     int
+	flushInterval time.Duration
+
+	wg        sync.WaitGroup
+	closeOnce sync.Once
+
+	dropped   atomic.Uint64
+	flushed   atomic.Uint64
+	flushErrs atomic.Uint64
+}
+
+// NewImpressionSink starts workers goroutines draining a queue of size
+// queueSize, flushing to Postgres whenever a batch reaches batchSize rows
+// or flushInterval elapses, whichever comes first.
+func NewImpressionSink(repo *LogsRepository, workers, queueSize, batchSize int, flushInterval time.Duration) *ImpressionSink {
+	s := &ImpressionSink{
+		repo:          repo,
+		queue:         make(chan ImpressionRecord, queueSize),
+		batchSize:     batchSize,
+		flushInterval: flushInterval,
+	}
+
+	for i := 0; i < workers; i++ {
+		s.wg.Add(1)
+		go s.runWorker()
+	}
+
+	return s
+}
+
+// Enqueue queues a single impression for batched insertion. It drops the
+// record (incrementing the Dropped counter) rather than blocking if the
+// queue is full.
+func (s *ImpressionSink) Enqueue(rec ImpressionRecord) {
+	select {
+	case s.queue <- rec:
+	default:
+		s.dropped.Add(1)
+	}
+}
+
+// EnqueueBatch queues multiple impressions, e.g. the whole suggestion list
+// shown for a single request.
+func (s *ImpressionSink) EnqueueBatch(recs []ImpressionRecord) {
+	for _, rec := range recs {
+		s.Enqueue(rec)
+	}
+}
+
+func (s *ImpressionSink) runWorker() {
+	defer s.wg.Done()
+
+	batch := make([]ImpressionRecord, 0, s.batchSize)
+	ticker := time.NewTicker(s.flushInterval)
+	defer ticker.Stop()
+
+	flush := func() {
+		if len(batch) == 0 {
+			return
+		}
+		start := time.Now()
+		if err := s.repo.InsertImpressionsBatch(context.Background(), batch); err != nil {
+			s.flushErrs.Add(1)
+			logging.For("impressions").Warn("flush failed", "rows", len(batch), "elapsedMs", time.Since(start).Milliseconds(), "err", err)
+		} else {
+			s.flushed.Add(uint64(len(batch)))
+		}
+		batch = batch[:0]
+	}
+
+	for {
+		select {
+		case rec, ok := <-s.queue:
+			if !ok {
+				flush()
+				return
+			}
+			batch = append(batch, rec)
+			if len(batch) >= s.batchSize {
+				flush()
+			}
+		case <-ticker.C:
+			flush()
+		}
+	}
+}
+
+// SinkMetrics is a point-in-time snapshot of the sink's health, suitable for
+// exposing on a debug/metrics endpoint.
+type SinkMetrics struct {
+	QueueDepth int
+	Dropped    uint64
+	Flushed    uint64
+	FlushErrs  uint64
+}
+
+// Metrics returns the current queue depth and cumulative counters.
+func (s *ImpressionSink) Metrics() SinkMetrics {
+	return SinkMetrics{
+		QueueDepth: len(s.queue),
+		Dropped:    s.dropped.Load(),
+		Flushed:    s.flushed.Load(),
+		FlushErrs:  s.flushErrs.Load(),
+	}
+}
+
+// Close stops accepting new records, flushes whatever is left in the
+// queue, and waits for every worker to finish, or until ctx is canceled.
+// Call it during graceful shutdown, alongside DB.Close.
+func (s *ImpressionSink) Close(ctx context.Context) error {
+	s.closeOnce.Do(func() { close(s.queue) })
+
+	done := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,63 @@
+package repositories
+
+import (
+	"context"
+	"time"
+)
+
+// TrainingExample is one rendered impression plus whether the user went on
+// to click it, for LearningService to fit RankerModel weights against.
+type TrainingExample struct {
+	NormalizedKeyword string
+	Suggestion        string
+	SuggestionType    string
+	Position          int
+	Clicked           bool
+	// FuzzySim is pg_trgm's similarity(keyword, suggestion), recomputed
+	// at query time rather than read back from a stored column - unlike
+	// PersonalFreq/TrendingZ, it only depends on the two strings
+	// themselves, so it's reconstructable long after the impression was
+	// logged and worth fitting a weight against.
+	FuzzySim float64
+}
+
+// GetTrainingExamples returns every impression logged since since, each
+// labeled by whether a click on the same (user, keyword, suggestion) pair
+// followed it within a few minutes. There's no session id linking an
+// impression to its click, so proximity in time is the best available
+// signal - good enough for the position-bias-corrected online fit
+// LearningService runs, not for per-session pairwise comparison.
+func (r *LogsRepository) GetTrainingExamples(ctx context.Context, since time.Time, limit int) ([]TrainingExample, error) {
+	query := `
+		SELECT i.normalized_keyword, i.suggestion, i.suggestion_type, i.position,
+		       (c.id IS NOT NULL) AS clicked,
+		       similarity(i.normalized_keyword, i.suggestion) AS fuzzy_sim
+		FROM search_impressions i
+		LEFT JOIN search_clicks c
+		  ON c.user_id IS NOT DISTINCT FROM i.user_id
+		 AND c.normalized_keyword = i.normalized_keyword
+		 AND c.clicked_suggestion = i.suggestion
+		 AND c.suggestion_type = i.suggestion_type
+		 AND c.created_at BETWEEN i.created_at AND i.created_at + INTERVAL '5 minutes'
+		WHERE i.created_at > $1
+		ORDER BY i.created_at DESC
+		LIMIT $2
+	`
+
+	rows, err := r.pool.Query(ctx, query, since, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var examples []TrainingExample
+	for rows.Next() {
+		var ex TrainingExample
+		if err := rows.Scan(&ex.NormalizedKeyword, &ex.Suggestion, &ex.SuggestionType, &ex.Position, &ex.Clicked, &ex.FuzzySim); err != nil {
+			return nil, err
+		}
+		examples = append(examples, ex)
+	}
+
+	return examples, rows.Err()
+}
@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/redis/go-redis/v9"
 )
@@ -56,17 +57,15 @@ func (r *LogsRepository) InsertImpressionsBatch(ctx context.Context, impressions
 	}
 	
 	query := `
-		INSERT INTO search_impressions 
+		INSERT INTO search_impressions
 		(user_id, keyword, normalized_keyword, suggestion, suggestion_type, world_id, position, created_at)
 		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
 	`
-	
-	batch := &pgxpool.Pool{}
-	_ = batch // Using individual inserts for simplicity
-	
+
 	now := time.Now()
+	batch := &pgx.Batch{}
 	for _, imp := range impressions {
-		_, err := r.pool.Exec(ctx, query,
+		batch.Queue(query,
 			imp.UserID,
 			imp.Keyword,
 			imp.NormalizedKeyword,
@@ -76,11 +75,17 @@ func (r *LogsRepository) InsertImpressionsBatch(ctx context.Context, impressions
 			imp.Position,
 			now,
 		)
-		if err != nil {
+	}
+
+	br := r.pool.SendBatch(ctx, batch)
+	defer br.Close()
+
+	for i := 0; i < batch.Len(); i++ {
+		if _, err := br.Exec(); err != nil {
 			return err
 		}
 	}
-	
+
 	return nil
 }
 
@@ -116,55 +121,64 @@ func (r *LogsRepository) InsertClick(ctx context.Context, userID *uuid.UUID, key
 	return err
 }
 
-// IncrementTrending increments the score for a keyword in the trending ZSET
-func (r *LogsRepository) IncrementTrending(ctx context.Context, normalizedKeyword string) error {
-	return r.redis.ZIncrBy(ctx, TrendingSearchKey, 1, normalizedKeyword).Err()
+// ClickRecord is a helper struct for batch click inserts, mirroring
+// ImpressionRecord.
+type ClickRecord struct {
+	UserID            *uuid.UUID
+	Keyword           string
+	NormalizedKeyword string
+	ClickedSuggestion string
+	SuggestionType    string
+	WorldID           *uuid.UUID
+	Position          *int
 }
 
-// GetTrendingKeywords gets keywords from the trending ZSET with prefix match
-func (r *LogsRepository) GetTrendingKeywords(ctx context.Context, prefix string, limit int) ([]TrendingKeyword, error) {
-	// Get all members and filter by prefix (Redis doesn't support prefix filtering in ZSET)
-	// For production, consider using a separate sorted set per prefix or Redis Search
-	members, err := r.redis.ZRevRangeWithScores(ctx, TrendingSearchKey, 0, 100).Result()
-	if err != nil {
-		return nil, err
-	}
-	
-	var results []TrendingKeyword
-	for _, m := range members {
-		keyword := m.Member.(string)
-		if len(keyword) >= len(prefix) && keyword[:len(prefix)] == prefix {
-			results = append(results, TrendingKeyword{
-				Keyword: keyword,
-				Score:   m.Score,
-			})
-			if len(results) >= limit {
-				break
-			}
-		}
+// InsertClicksBatch inserts multiple click records in a batch, the same
+// pgx.Batch pattern InsertImpressionsBatch uses. Used by cmd/logs-runner
+// when draining a backlog of clicks rather than logging them one at a
+// time off the request path.
+func (r *LogsRepository) InsertClicksBatch(ctx context.Context, clicks []ClickRecord) error {
+	if len(clicks) == 0 {
+		return nil
 	}
-	
-	return results, nil
-}
 
-// GetTopTrending gets the top N trending keywords
-func (r *LogsRepository) GetTopTrending(ctx context.Context, limit int) ([]TrendingKeyword, error) {
-	members, err := r.redis.ZRevRangeWithScores(ctx, TrendingSearchKey, 0, int64(limit-1)).Result()
-	if err != nil {
-		return nil, err
+	query := `
+		INSERT INTO search_clicks
+		(user_id, keyword, normalized_keyword, clicked_suggestion, suggestion_type, world_id, position, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`
+
+	now := time.Now()
+	batch := &pgx.Batch{}
+	for _, click := range clicks {
+		batch.Queue(query,
+			click.UserID,
+			click.Keyword,
+			click.NormalizedKeyword,
+			click.ClickedSuggestion,
+			click.SuggestionType,
+			click.WorldID,
+			click.Position,
+			now,
+		)
 	}
-	
-	results := make([]TrendingKeyword, len(members))
-	for i, m := range members {
-		results[i] = TrendingKeyword{
-			Keyword: m.Member.(string),
-			Score:   m.Score,
+
+	br := r.pool.SendBatch(ctx, batch)
+	defer br.Close()
+
+	for i := 0; i < batch.Len(); i++ {
+		if _, err := br.Exec(); err != nil {
+			return err
 		}
 	}
-	
-	return results, nil
+
+	return nil
 }
 
+// IncrementTrending, GetTrendingKeywords, and GetTopTrending now live in
+// trending.go alongside the sharded prefix index and time-decay logic they
+// share.
+
 // TrendingKeyword holds a keyword and its trending score
 type TrendingKeyword struct {
 	Keyword string
@@ -0,0 +1,75 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/worlds-search/backend/models"
+)
+
+// RankerModelRepository persists the single active RankerModel trained by
+// LearningService. There is only ever one "current" model - retraining
+// replaces it rather than appending a history table, matching the size of
+// problem this service actually has today.
+type RankerModelRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewRankerModelRepository creates a new RankerModelRepository
+func NewRankerModelRepository(pool *pgxpool.Pool) *RankerModelRepository {
+	return &RankerModelRepository{pool: pool}
+}
+
+// GetActiveModel returns the currently active ranker model, or
+// pgx.ErrNoRows if retraining has never run.
+func (r *RankerModelRepository) GetActiveModel(ctx context.Context) (*models.RankerModel, error) {
+	query := `
+		SELECT version, prefix_weight, personal_weight, trending_weight, fuzzy_weight, ctr_weight, trained_at, training_rows
+		FROM ranker_models
+		ORDER BY version DESC
+		LIMIT 1
+	`
+
+	var m models.RankerModel
+	err := r.pool.QueryRow(ctx, query).Scan(
+		&m.Version,
+		&m.PrefixWeight,
+		&m.PersonalWeight,
+		&m.TrendingWeight,
+		&m.FuzzyWeight,
+		&m.CTRWeight,
+		&m.TrainedAt,
+		&m.TrainingRows,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, pgx.ErrNoRows
+		}
+		return nil, err
+	}
+
+	return &m, nil
+}
+
+// SaveModel inserts a new model version, becoming the active one.
+func (r *RankerModelRepository) SaveModel(ctx context.Context, m models.RankerModel) error {
+	query := `
+		INSERT INTO ranker_models
+		(version, prefix_weight, personal_weight, trending_weight, fuzzy_weight, ctr_weight, trained_at, training_rows)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`
+
+	_, err := r.pool.Exec(ctx, query,
+		m.Version,
+		m.PrefixWeight,
+		m.PersonalWeight,
+		m.TrendingWeight,
+		m.FuzzyWeight,
+		m.CTRWeight,
+		m.TrainedAt,
+		m.TrainingRows,
+	)
+	return err
+}
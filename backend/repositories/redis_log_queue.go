@@ -0,0 +1,82 @@
+package repositories
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	// ImpressionQueueKey and ClickQueueKey are the Redis lists
+	// RedisLogQueue.Enqueue* pushes onto and cmd/logs-runner drains from.
+	ImpressionQueueKey = "logs:queue:impressions"
+	ClickQueueKey      = "logs:queue:clicks"
+)
+
+// ImpressionQueue is implemented by both ImpressionSink (bounded,
+// best-effort batching straight to Postgres) and RedisLogQueue (buffered
+// through Redis for cmd/logs-runner to drain), so SuggestionsService can
+// be wired to either without caring which is configured (see
+// config.Config.LogQueueBackend).
+type ImpressionQueue interface {
+	EnqueueBatch(recs []ImpressionRecord)
+}
+
+// ClickQueue is implemented by RedisLogQueue. SearchService only uses one
+// when config.Config.LogQueueBackend selects "redis" - otherwise it writes
+// clicks straight to Postgres via LogsRepository.InsertClick, same as
+// before RedisLogQueue existed.
+type ClickQueue interface {
+	EnqueueClick(ctx context.Context, rec ClickRecord) error
+}
+
+// RedisLogQueue buffers impression/click records in Redis lists instead of
+// writing them to Postgres in-process. It exists for deployments that run
+// cmd/logs-runner as a separate process: the API handlers push here
+// (cheap, no Postgres round trip on the request path) and logs-runner
+// drains the lists in its own batches.
+//
+// This is an alternative to ImpressionSink/direct InsertClick, not a
+// replacement - main.go defaults to the in-process path (LOG_QUEUE_BACKEND
+// unset or "inprocess"), which already gives the API process a bounded,
+// drop-when-full path straight to Postgres without needing a second binary.
+// Set LOG_QUEUE_BACKEND=redis to wire RedisLogQueue in instead, decoupling
+// logging entirely from the API process's own Postgres connection pool.
+type RedisLogQueue struct {
+	redis *redis.Client
+}
+
+// NewRedisLogQueue creates a new RedisLogQueue.
+func NewRedisLogQueue(redis *redis.Client) *RedisLogQueue {
+	return &RedisLogQueue{redis: redis}
+}
+
+// EnqueueImpression pushes a single impression record onto ImpressionQueueKey.
+func (q *RedisLogQueue) EnqueueImpression(ctx context.Context, rec ImpressionRecord) error {
+	return q.push(ctx, ImpressionQueueKey, rec)
+}
+
+// EnqueueClick pushes a single click record onto ClickQueueKey.
+func (q *RedisLogQueue) EnqueueClick(ctx context.Context, rec ClickRecord) error {
+	return q.push(ctx, ClickQueueKey, rec)
+}
+
+// EnqueueBatch implements ImpressionQueue with the same signature as
+// ImpressionSink.EnqueueBatch so SuggestionsService can be wired to either
+// sink interchangeably. Each record is pushed with context.Background()
+// and best-effort - a failed push is just a dropped impression, the same
+// as ImpressionSink dropping under a full queue.
+func (q *RedisLogQueue) EnqueueBatch(recs []ImpressionRecord) {
+	for _, rec := range recs {
+		_ = q.EnqueueImpression(context.Background(), rec)
+	}
+}
+
+func (q *RedisLogQueue) push(ctx context.Context, key string, rec any) error {
+	payload, err := json.Marshal(rec)
+	if err != nil {
+		return err
+	}
+	return q.redis.RPush(ctx, key, payload).Err()
+}
@@ -0,0 +1,72 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// ResumeToken marks how far a bulk reindex has scanned through the worlds
+// table, as a (created_at, id) keyset pagination cursor - the same
+// ordering WorldsRepository.StreamWorldsBatch paginates by.
+type ResumeToken struct {
+	LastCreatedAt time.Time
+	LastID        uuid.UUID
+}
+
+// ReindexStateRepository persists the single in-flight reindex's resume
+// token, so an interrupted run can pick up where it stopped instead of
+// re-scanning the whole worlds table. A run that completes clears it.
+type ReindexStateRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewReindexStateRepository creates a new ReindexStateRepository
+func NewReindexStateRepository(pool *pgxpool.Pool) *ReindexStateRepository {
+	return &ReindexStateRepository{pool: pool}
+}
+
+// GetResumeToken returns the last saved resume token, or nil if none is
+// saved (no reindex has run yet, or the last one completed and cleared it).
+func (r *ReindexStateRepository) GetResumeToken(ctx context.Context) (*ResumeToken, error) {
+	query := `
+		SELECT last_created_at, last_id
+		FROM reindex_state
+		WHERE id = 1
+	`
+
+	var token ResumeToken
+	err := r.pool.QueryRow(ctx, query).Scan(&token.LastCreatedAt, &token.LastID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &token, nil
+}
+
+// SaveResumeToken upserts the resume token for the single reindex_state
+// row. There is only ever one active cursor - concurrent reindexes aren't
+// supported (see indexer.JobRegistry).
+func (r *ReindexStateRepository) SaveResumeToken(ctx context.Context, token ResumeToken) error {
+	query := `
+		INSERT INTO reindex_state (id, last_created_at, last_id)
+		VALUES (1, $1, $2)
+		ON CONFLICT (id) DO UPDATE SET last_created_at = $1, last_id = $2
+	`
+	_, err := r.pool.Exec(ctx, query, token.LastCreatedAt, token.LastID)
+	return err
+}
+
+// ClearResumeToken removes the saved cursor, so the next reindex starts
+// from the beginning of the worlds table again.
+func (r *ReindexStateRepository) ClearResumeToken(ctx context.Context) error {
+	_, err := r.pool.Exec(ctx, `DELETE FROM reindex_state WHERE id = 1`)
+	return err
+}
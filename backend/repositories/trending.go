@@ -0,0 +1,335 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	// TrendingEpochKey stores the Unix-seconds reference point that all
+	// decayed trending scores are computed relative to. Advancing it (see
+	// renormalizeTrendingEpoch) keeps exp() arguments from growing without
+	// bound as the service runs for a long time.
+	TrendingEpochKey = "trending:epoch"
+
+	// trendingHalfLife controls how fast a keyword's trending weight
+	// decays: after one trendingHalfLife with no further searches, its
+	// score is worth 1/e (about 37%) of what it was, since decayWeight
+	// uses exp(elapsed/trendingHalfLife) rather than a base-2 exponent.
+	trendingHalfLife = 6 * time.Hour
+
+	// trendingShardLen is the length of the normalized-keyword prefix used
+	// to shard the trending index, e.g. "garmin" -> shard "gar".
+	trendingShardLen = 3
+
+	// trendingMigratedKey guards the one-time backfill from the legacy
+	// flat trending_search ZSET into the sharded prefix index.
+	trendingMigratedKey = "trending:migrated"
+)
+
+// trendingPrefixKey returns the Redis key for the shard that a normalized
+// keyword falls into, e.g. "trending:pfx:gar". Keywords shorter than
+// trendingShardLen get their own (shorter) shard.
+func trendingPrefixKey(normalizedKeyword string) string {
+	n := len(normalizedKeyword)
+	if n > trendingShardLen {
+		n = trendingShardLen
+	}
+	return "trending:pfx:" + normalizedKeyword[:n]
+}
+
+// trendingEpoch returns the current decay epoch, initializing it to now if
+// it hasn't been set yet.
+func (r *LogsRepository) trendingEpoch(ctx context.Context) (time.Time, error) {
+	val, err := r.redis.Get(ctx, TrendingEpochKey).Result()
+	if err == nil {
+		sec, perr := strconv.ParseInt(val, 10, 64)
+		if perr == nil {
+			return time.Unix(sec, 0), nil
+		}
+	}
+
+	now := time.Now()
+	if err := r.redis.SetNX(ctx, TrendingEpochKey, now.Unix(), 0).Err(); err != nil {
+		return time.Time{}, err
+	}
+	return now, nil
+}
+
+// decayWeight returns exp((at - epoch) / halflife), the multiplier applied
+// to a +1 increment so that older increments count for less once read back.
+func decayWeight(at, epoch time.Time) float64 {
+	elapsed := at.Sub(epoch).Seconds()
+	return math.Exp(elapsed / trendingHalfLife.Seconds())
+}
+
+// IncrementTrending increments the decayed score for a keyword in both the
+// global trending ZSET and its prefix shard, in a single MULTI/EXEC so the
+// two never drift out of sync.
+func (r *LogsRepository) IncrementTrending(ctx context.Context, normalizedKeyword string) error {
+	if normalizedKeyword == "" {
+		return nil
+	}
+
+	epoch, err := r.trendingEpoch(ctx)
+	if err != nil {
+		return err
+	}
+	weight := decayWeight(time.Now(), epoch)
+
+	_, err = r.redis.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.ZIncrBy(ctx, TrendingSearchKey, weight, normalizedKeyword)
+		pipe.ZIncrBy(ctx, trendingPrefixKey(normalizedKeyword), weight, normalizedKeyword)
+		return nil
+	})
+	return err
+}
+
+// redisGlobMetaReplacer escapes the characters SCAN's MATCH pattern treats
+// specially (glob-style, not regex: `*`, `?`, `[`) so a user-supplied
+// prefix is always matched literally. `\` must be escaped first so its own
+// escaping of the others doesn't get re-escaped.
+var redisGlobMetaReplacer = strings.NewReplacer(
+	`\`, `\\`,
+	`*`, `\*`,
+	`?`, `\?`,
+	`[`, `\[`,
+)
+
+// trendingShardKeysForPrefix returns every shard key that could hold a
+// keyword starting with prefix. Once prefix reaches trendingShardLen it
+// maps onto exactly one shard (the fast path: a single ZREVRANGE).
+// Shorter prefixes don't identify a shard by themselves — writes always
+// shard by the full keyword's first trendingShardLen characters, so e.g.
+// prefix "ga" must check "gar", "gas", "gam", etc. — so we SCAN for every
+// shard key whose suffix starts with prefix. prefix is escaped before
+// being spliced into the MATCH pattern so a keyword containing `*`, `?`,
+// or `[` (none of which NormalizeKeyword strips) can't widen the scan to
+// unrelated shards.
+func (r *LogsRepository) trendingShardKeysForPrefix(ctx context.Context, prefix string) ([]string, error) {
+	if len(prefix) >= trendingShardLen {
+		return []string{trendingPrefixKey(prefix)}, nil
+	}
+
+	pattern := "trending:pfx:" + redisGlobMetaReplacer.Replace(prefix) + "*"
+	var keys []string
+	iter := r.redis.Scan(ctx, 0, pattern, 100).Iterator()
+	for iter.Next(ctx) {
+		keys = append(keys, iter.Val())
+	}
+	if err := iter.Err(); err != nil {
+		return nil, err
+	}
+	return keys, nil
+}
+
+// GetTrendingKeywords returns up to limit keywords starting with prefix,
+// ordered by decayed trending score descending. For prefixes at least
+// trendingShardLen long this is a single ZREVRANGE over the whole shard
+// prefix falls into; shorter prefixes span multiple shards, so their
+// members are merged and re-sorted before truncating to limit.
+func (r *LogsRepository) GetTrendingKeywords(ctx context.Context, prefix string, limit int) ([]TrendingKeyword, error) {
+	if prefix == "" {
+		return r.GetTopTrending(ctx, limit)
+	}
+
+	epoch, err := r.trendingEpoch(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	shardKeys, err := r.trendingShardKeysForPrefix(ctx, prefix)
+	if err != nil {
+		return nil, err
+	}
+
+	var members []redis.Z
+	for _, shardKey := range shardKeys {
+		shardMembers, err := r.redis.ZRevRangeWithScores(ctx, shardKey, 0, -1).Result()
+		if err != nil {
+			return nil, err
+		}
+		members = append(members, shardMembers...)
+	}
+
+	now := time.Now()
+	decodeFactor := decayWeight(now, epoch)
+
+	results := make([]TrendingKeyword, 0, len(members))
+	for _, m := range members {
+		keyword := m.Member.(string)
+		if len(keyword) < len(prefix) || keyword[:len(prefix)] != prefix {
+			continue
+		}
+		results = append(results, TrendingKeyword{
+			Keyword: keyword,
+			Score:   m.Score / decodeFactor,
+		})
+	}
+
+	sort.Slice(results, func(i, j int) bool { return results[i].Score > results[j].Score })
+	if len(results) > limit {
+		results = results[:limit]
+	}
+
+	return results, nil
+}
+
+// GetTopTrending gets the top N trending keywords, decaying each member's
+// raw stored score back down to a comparable "decayed count".
+func (r *LogsRepository) GetTopTrending(ctx context.Context, limit int) ([]TrendingKeyword, error) {
+	epoch, err := r.trendingEpoch(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	members, err := r.redis.ZRevRangeWithScores(ctx, TrendingSearchKey, 0, int64(limit-1)).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	decodeFactor := decayWeight(time.Now(), epoch)
+
+	results := make([]TrendingKeyword, len(members))
+	for i, m := range members {
+		results[i] = TrendingKeyword{
+			Keyword: m.Member.(string),
+			Score:   m.Score / decodeFactor,
+		}
+	}
+
+	return results, nil
+}
+
+// RenormalizeTrendingEpoch rebases every trending ZSET (global and prefix
+// shards) onto a fresh epoch, dividing each member's score by the decay
+// that accumulated since the old epoch. This is what keeps exp() from
+// overflowing float64 if the process runs for weeks without a restart. It
+// should be called periodically from a background goroutine (see
+// StartTrendingEpochRenormalizer).
+func (r *LogsRepository) RenormalizeTrendingEpoch(ctx context.Context) error {
+	oldEpoch, err := r.trendingEpoch(ctx)
+	if err != nil {
+		return err
+	}
+	newEpoch := time.Now()
+	rescale := 1.0 / decayWeight(newEpoch, oldEpoch)
+	if rescale == 1.0 {
+		return nil
+	}
+
+	keys, err := r.trendingKeys(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, key := range keys {
+		if err := r.rescaleZSet(ctx, key, rescale); err != nil {
+			return fmt.Errorf("repositories: renormalize %s: %w", key, err)
+		}
+	}
+
+	return r.redis.Set(ctx, TrendingEpochKey, newEpoch.Unix(), 0).Err()
+}
+
+// trendingKeys returns the global trending key plus every prefix shard
+// currently in use.
+func (r *LogsRepository) trendingKeys(ctx context.Context) ([]string, error) {
+	keys := []string{TrendingSearchKey}
+
+	iter := r.redis.Scan(ctx, 0, "trending:pfx:*", 100).Iterator()
+	for iter.Next(ctx) {
+		keys = append(keys, iter.Val())
+	}
+	if err := iter.Err(); err != nil {
+		return nil, err
+	}
+	return keys, nil
+}
+
+// rescaleZSet multiplies every member's score in key by factor.
+func (r *LogsRepository) rescaleZSet(ctx context.Context, key string, factor float64) error {
+	members, err := r.redis.ZRangeWithScores(ctx, key, 0, -1).Result()
+	if err != nil {
+		return err
+	}
+	if len(members) == 0 {
+		return nil
+	}
+
+	_, err = r.redis.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		for _, m := range members {
+			pipe.ZAdd(ctx, key, redis.Z{Member: m.Member, Score: m.Score * factor})
+		}
+		return nil
+	})
+	return err
+}
+
+// MigrateLegacyTrending backfills the sharded prefix index from the
+// pre-existing flat trending_search ZSET, treating each member's current
+// (un-decayed) score as its initial count under a freshly-set epoch. It is
+// a no-op after the first successful run.
+func (r *LogsRepository) MigrateLegacyTrending(ctx context.Context) error {
+	migrated, err := r.redis.SetNX(ctx, trendingMigratedKey, 1, 0).Result()
+	if err != nil {
+		return err
+	}
+	if !migrated {
+		return nil
+	}
+
+	members, err := r.redis.ZRangeWithScores(ctx, TrendingSearchKey, 0, -1).Result()
+	if err != nil {
+		return err
+	}
+	if len(members) == 0 {
+		return nil
+	}
+
+	byShard := make(map[string][]redis.Z)
+	for _, m := range members {
+		keyword, ok := m.Member.(string)
+		if !ok || keyword == "" {
+			continue
+		}
+		shard := trendingPrefixKey(keyword)
+		byShard[shard] = append(byShard[shard], redis.Z{Member: keyword, Score: m.Score})
+	}
+
+	_, err = r.redis.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		for shard, shardMembers := range byShard {
+			for _, sm := range shardMembers {
+				pipe.ZAdd(ctx, shard, sm)
+			}
+		}
+		return nil
+	})
+	return err
+}
+
+// StartTrendingEpochRenormalizer runs RenormalizeTrendingEpoch on interval
+// until ctx is canceled. Run it as a single background goroutine from
+// main.go; it's safe to skip entirely (decay just degrades gracefully once
+// exp() starts producing +Inf after a very long uptime).
+func (r *LogsRepository) StartTrendingEpochRenormalizer(ctx context.Context, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				_ = r.RenormalizeTrendingEpoch(ctx)
+			}
+		}
+	}()
+}
@@ -4,8 +4,10 @@ import (
 	"context"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/worlds-search/backend/models"
+	"github.com/worlds-search/backend/telemetry"
 )
 
 // WorldsRepository handles database operations for worlds
@@ -44,6 +46,58 @@ func (r *WorldsRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.W
 type WorldWithSimilarity struct {
 	World      models.World
 	Similarity float64
+
+	// PrefixRank, TrgmRank, and BigramRank are populated by SearchRRF only
+	// (0 if the world wasn't returned by that source's leg) so callers can
+	// show which signals a world's rrf_score came from. Every other
+	// WorldsRepository search method leaves them at their zero value.
+	PrefixRank int
+	TrgmRank   int
+	BigramRank int
+}
+
+// StreamWorldsBatch returns up to batchSize worlds ordered by
+// (created_at, id), starting strictly after after (or from the very
+// beginning if after is nil). This is the keyset-pagination cursor the
+// bulk reindex subsystem walks the whole table with, matching the
+// ResumeToken it checkpoints.
+func (r *WorldsRepository) StreamWorldsBatch(ctx context.Context, after *ResumeToken, batchSize int) ([]models.World, error) {
+	ctx, span := telemetry.StartSpan(ctx, "worlds_repository.stream_worlds_batch")
+	defer span.End()
+
+	var rows pgx.Rows
+	var err error
+	if after == nil {
+		rows, err = r.pool.Query(ctx, `
+			SELECT id, title, description, created_at
+			FROM worlds
+			ORDER BY created_at, id
+			LIMIT $1
+		`, batchSize)
+	} else {
+		rows, err = r.pool.Query(ctx, `
+			SELECT id, title, description, created_at
+			FROM worlds
+			WHERE (created_at, id) > ($1, $2)
+			ORDER BY created_at, id
+			LIMIT $3
+		`, after.LastCreatedAt, after.LastID, batchSize)
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var worlds []models.World
+	for rows.Next() {
+		var w models.World
+		if err := rows.Scan(&w.ID, &w.Title, &w.Description, &w.CreatedAt); err != nil {
+			return nil, err
+		}
+		worlds = append(worlds, w)
+	}
+
+	return worlds, rows.Err()
 }
 
 // SearchByPrefix finds worlds where title starts with the given prefix
@@ -118,13 +172,21 @@ func (r *WorldsRepository) SearchByFuzzy(ctx context.Context, keyword string, li
 	return results, rows.Err()
 }
 
-// SearchCombined searches worlds using both prefix and fuzzy matching
-// This is used for the search results page
-func (r *WorldsRepository) SearchCombined(ctx context.Context, keyword string, limit int) ([]WorldWithSimilarity, error) {
+// SearchCombined searches worlds using both prefix and fuzzy matching.
+// This is used for the search results page. fuzzyMode raises the pg_trgm
+// similarity threshold for models.FuzzyLow/models.FuzzyOff (0.1 -> 0.5);
+// models.FuzzyOff additionally drops the result through exactTokenFilter
+// since pg_trgm has no per-query way to disable fuzziness outright.
+func (r *WorldsRepository) SearchCombined(ctx context.Context, keyword string, limit int, fuzzyMode models.FuzzyMode) ([]WorldWithSimilarity, error) {
+	ctx, span := telemetry.StartSpan(ctx, "worlds_repository.search_combined")
+	defer span.End()
+
+	threshold := fuzzyThreshold(fuzzyMode)
+
 	// Use a combined query that gets prefix matches first, then fuzzy matches
 	query := `
 		WITH prefix_matches AS (
-			SELECT id, title, description, created_at, 
+			SELECT id, title, description, created_at,
 			       1.0::float as sim,
 			       1 as match_type
 			FROM worlds
@@ -135,7 +197,7 @@ func (r *WorldsRepository) SearchCombined(ctx context.Context, keyword string, l
 			       similarity(LOWER(title), LOWER($1)) as sim,
 			       2 as match_type
 			FROM worlds
-			WHERE similarity(LOWER(title), LOWER($1)) > 0.1
+			WHERE similarity(LOWER(title), LOWER($1)) > $3
 			  AND id NOT IN (SELECT id FROM prefix_matches)
 		),
 		contains_matches AS (
@@ -159,7 +221,7 @@ func (r *WorldsRepository) SearchCombined(ctx context.Context, keyword string, l
 		LIMIT $2
 	`
 
-	rows, err := r.pool.Query(ctx, query, keyword, limit)
+	rows, err := r.pool.Query(ctx, query, keyword, limit, threshold)
 	if err != nil {
 		return nil, err
 	}
@@ -179,8 +241,122 @@ func (r *WorldsRepository) SearchCombined(ctx context.Context, keyword string, l
 		}
 		results = append(results, ws)
 	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
 
-	return results, rows.Err()
+	if fuzzyMode == models.FuzzyOff {
+		results = exactTokenFilter(results, keyword)
+	}
+
+	return results, nil
+}
+
+// rrfK is the Reciprocal Rank Fusion damping constant SearchRRF uses -
+// 60 is the value the original RRF paper (Cormack, Clarke & Buettcher,
+// 2009) tuned against and the one most RRF implementations default to.
+const rrfK = 60.0
+
+// SearchRRF combines prefix, pg_trgm, and pg_bigm matches via Reciprocal
+// Rank Fusion: each source ranks its own candidates independently, and a
+// world's final score is the sum of 1/(k + rank) across every source
+// that returned it - rewarding worlds that rank well across multiple
+// signals over one source's single highest-similarity outlier, without
+// needing the three sources' scores to be on comparable scales (unlike
+// SearchCombined's match_type tiering, which has to pick one score to sort
+// by). k overrides the damping constant; pass 0 (or any value <= 0) to use
+// the default rrfK. Each result's PrefixRank/TrgmRank/BigramRank report
+// the 1-based rank it held in that source (0 if the source didn't return
+// it), so a caller can show why a world ranked where it did. fuzzyMode
+// only affects the pg_trgm leg's threshold and, for models.FuzzyOff, runs
+// the result through exactTokenFilter same as the other combined search
+// methods.
+func (r *WorldsRepository) SearchRRF(ctx context.Context, keyword string, limit int, fuzzyMode models.FuzzyMode, k float64) ([]WorldWithSimilarity, error) {
+	ctx, span := telemetry.StartSpan(ctx, "worlds_repository.search_rrf")
+	defer span.End()
+
+	if k <= 0 {
+		k = rrfK
+	}
+
+	threshold := fuzzyThreshold(fuzzyMode)
+
+	query := `
+		WITH prefix_ranked AS (
+			SELECT id, title, description, created_at,
+			       ROW_NUMBER() OVER (ORDER BY created_at DESC) AS rnk
+			FROM worlds
+			WHERE LOWER(title) LIKE LOWER($1) || '%'
+			LIMIT $2
+		),
+		trgm_ranked AS (
+			SELECT id, title, description, created_at,
+			       ROW_NUMBER() OVER (ORDER BY similarity(LOWER(title), LOWER($1)) DESC) AS rnk
+			FROM worlds
+			WHERE similarity(LOWER(title), LOWER($1)) > $4
+			LIMIT $2
+		),
+		bigram_ranked AS (
+			SELECT id, title, description, created_at,
+			       ROW_NUMBER() OVER (ORDER BY bigm_similarity(LOWER(title), LOWER($1)) DESC) AS rnk
+			FROM worlds
+			WHERE LOWER(title) LIKE LOWER(likequery($1))
+			LIMIT $2
+		),
+		combined AS (
+			SELECT id, title, description, created_at FROM prefix_ranked
+			UNION
+			SELECT id, title, description, created_at FROM trgm_ranked
+			UNION
+			SELECT id, title, description, created_at FROM bigram_ranked
+		)
+		SELECT c.id, c.title, c.description, c.created_at,
+		       COALESCE(1.0 / ($3 + p.rnk), 0)
+		     + COALESCE(1.0 / ($3 + t.rnk), 0)
+		     + COALESCE(1.0 / ($3 + b.rnk), 0) AS rrf_score,
+		       COALESCE(p.rnk, 0) AS prefix_rank,
+		       COALESCE(t.rnk, 0) AS trgm_rank,
+		       COALESCE(b.rnk, 0) AS bigram_rank
+		FROM combined c
+		LEFT JOIN prefix_ranked p ON p.id = c.id
+		LEFT JOIN trgm_ranked t ON t.id = c.id
+		LEFT JOIN bigram_ranked b ON b.id = c.id
+		ORDER BY rrf_score DESC, c.created_at DESC
+		LIMIT $2
+	`
+
+	rows, err := r.pool.Query(ctx, query, keyword, limit, k, threshold)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var results []WorldWithSimilarity
+	for rows.Next() {
+		var ws WorldWithSimilarity
+		if err := rows.Scan(
+			&ws.World.ID,
+			&ws.World.Title,
+			&ws.World.Description,
+			&ws.World.CreatedAt,
+			&ws.Similarity,
+			&ws.PrefixRank,
+			&ws.TrgmRank,
+			&ws.BigramRank,
+		); err != nil {
+			return nil, err
+		}
+		results = append(results, ws)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if fuzzyMode == models.FuzzyOff {
+		results = exactTokenFilter(results, keyword)
+	}
+
+	return results, nil
 }
 
 // ============================================================
@@ -188,21 +364,27 @@ func (r *WorldsRepository) SearchCombined(ctx context.Context, keyword string, l
 // ============================================================
 
 // FindByTitleTrgm searches worlds using pg_trgm similarity matching
-// Returns worlds sorted by similarity score descending
-// Uses similarity threshold of 0.1 for better recall with short keywords
-func (r *WorldsRepository) FindByTitleTrgm(ctx context.Context, keyword string, limit int) ([]WorldWithSimilarity, error) {
+// Returns worlds sorted by similarity score descending. fuzzyMode raises
+// the similarity threshold for models.FuzzyLow/models.FuzzyOff and, for
+// models.FuzzyOff, additionally runs the result through exactTokenFilter.
+func (r *WorldsRepository) FindByTitleTrgm(ctx context.Context, keyword string, limit int, fuzzyMode models.FuzzyMode) ([]WorldWithSimilarity, error) {
+	ctx, span := telemetry.StartSpan(ctx, "worlds_repository.find_by_title_trgm")
+	defer span.End()
+
+	threshold := fuzzyThreshold(fuzzyMode)
+
 	// Use similarity() function directly with threshold comparison
 	// This avoids relying on the % operator which uses a global threshold
 	query := `
-		SELECT id, title, description, created_at, 
+		SELECT id, title, description, created_at,
 		       similarity(LOWER(title), LOWER($1)) as sim
 		FROM worlds
-		WHERE similarity(LOWER(title), LOWER($1)) > 0.1
+		WHERE similarity(LOWER(title), LOWER($1)) > $3
 		ORDER BY sim DESC, created_at DESC
 		LIMIT $2
 	`
 
-	rows, err := r.pool.Query(ctx, query, keyword, limit)
+	rows, err := r.pool.Query(ctx, query, keyword, limit, threshold)
 	if err != nil {
 		return nil, err
 	}
@@ -222,14 +404,26 @@ func (r *WorldsRepository) FindByTitleTrgm(ctx context.Context, keyword string,
 		}
 		results = append(results, ws)
 	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
 
-	return results, rows.Err()
+	if fuzzyMode == models.FuzzyOff {
+		results = exactTokenFilter(results, keyword)
+	}
+
+	return results, nil
 }
 
 // FindByTitleBigram searches worlds using pg_bigm bigram matching
 // Uses the likequery function to convert search term to LIKE pattern
-// Returns worlds sorted by bigm_similarity score descending
-func (r *WorldsRepository) FindByTitleBigram(ctx context.Context, keyword string, limit int) ([]WorldWithSimilarity, error) {
+// Returns worlds sorted by bigm_similarity score descending. pg_bigm has no
+// adjustable numeric threshold like pg_trgm's similarity(), so fuzzyMode
+// only affects models.FuzzyOff here, via exactTokenFilter.
+func (r *WorldsRepository) FindByTitleBigram(ctx context.Context, keyword string, limit int, fuzzyMode models.FuzzyMode) ([]WorldWithSimilarity, error) {
+	ctx, span := telemetry.StartSpan(ctx, "worlds_repository.find_by_title_bigram")
+	defer span.End()
+
 	// pg_bigm uses likequery() to convert keyword to LIKE pattern
 	// and bigm_similarity() to get similarity score
 	// Use LOWER() for case-insensitive matching
@@ -263,5 +457,13 @@ func (r *WorldsRepository) FindByTitleBigram(ctx context.Context, keyword string
 		results = append(results, ws)
 	}
 
-	return results, rows.Err()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if fuzzyMode == models.FuzzyOff {
+		results = exactTokenFilter(results, keyword)
+	}
+
+	return results, nil
 }
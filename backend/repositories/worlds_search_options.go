@@ -0,0 +1,139 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/worlds-search/backend/models"
+)
+
+// SearchWithOptions backs the structured search DSL (POST
+// /api/search/results): keyword plus filter clauses, a sort spec, and
+// from/size pagination. It returns the page of results plus the total
+// match count (via COUNT(*) OVER()) so the caller can paginate.
+//
+// Only the created_at filter is translated into SQL today - tag, owner,
+// and world_type clauses are accepted but ignored since the worlds table
+// has no columns for them yet.
+func (r *WorldsRepository) SearchWithOptions(
+	ctx context.Context,
+	keyword string,
+	filters []models.FilterClause,
+	sort models.SortMode,
+	from, size int,
+) ([]WorldWithSimilarity, int, error) {
+	var (
+		conditions []string
+		args       []interface{}
+	)
+
+	args = append(args, keyword)
+	if keyword != "" {
+		conditions = append(conditions, fmt.Sprintf("(LOWER(title) LIKE '%%' || LOWER($%d) || '%%' OR LOWER(description) LIKE '%%' || LOWER($%d) || '%%')", 1, 1))
+	}
+
+	for _, f := range filters {
+		if f.Field != models.FilterCreatedAt {
+			continue // tag/owner/world_type: no backing column yet
+		}
+		t, err := time.Parse(time.RFC3339, f.Value)
+		if err != nil {
+			return nil, 0, fmt.Errorf("repositories: invalid created_at filter value %q: %w", f.Value, err)
+		}
+		args = append(args, t)
+		switch f.Op {
+		case models.OpGreaterThan:
+			conditions = append(conditions, fmt.Sprintf("created_at > $%d", len(args)))
+		case models.OpLessThan:
+			conditions = append(conditions, fmt.Sprintf("created_at < $%d", len(args)))
+		case models.OpEquals:
+			conditions = append(conditions, fmt.Sprintf("created_at = $%d", len(args)))
+		default:
+			args = args[:len(args)-1] // unrecognized op: drop the arg we just added
+		}
+	}
+
+	where := "TRUE"
+	if len(conditions) > 0 {
+		where = strings.Join(conditions, " AND ")
+	}
+
+	orderBy := "created_at DESC"
+	switch sort {
+	case models.SortRelevance:
+		if keyword != "" {
+			orderBy = fmt.Sprintf("similarity(LOWER(title), LOWER($1)) DESC, created_at DESC")
+		}
+	case models.SortPopularity:
+		// No popularity/view-count column exists yet; fall back to
+		// recency rather than silently returning an arbitrary order.
+		orderBy = "created_at DESC"
+	case models.SortRecency, "":
+		orderBy = "created_at DESC"
+	}
+
+	args = append(args, size, from)
+	limitIdx := len(args) - 1
+	offsetIdx := len(args)
+
+	query := fmt.Sprintf(`
+		SELECT id, title, description, created_at, COUNT(*) OVER() AS total
+		FROM worlds
+		WHERE %s
+		ORDER BY %s
+		LIMIT $%d OFFSET $%d
+	`, where, orderBy, limitIdx, offsetIdx)
+
+	rows, err := r.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	var (
+		results []WorldWithSimilarity
+		total   int
+	)
+	for rows.Next() {
+		var ws WorldWithSimilarity
+		if err := rows.Scan(&ws.World.ID, &ws.World.Title, &ws.World.Description, &ws.World.CreatedAt, &total); err != nil {
+			return nil, 0, err
+		}
+		results = append(results, ws)
+	}
+
+	return results, total, rows.Err()
+}
+
+// GetCreatedAtFacet buckets worlds matching keyword by creation month,
+// e.g. "2025-01" -> 42. It's the only facet dimension the current schema
+// supports; other requested facet names are reported as empty by the
+// caller.
+func (r *WorldsRepository) GetCreatedAtFacet(ctx context.Context, keyword string) ([]models.FacetBucket, error) {
+	query := `
+		SELECT to_char(date_trunc('month', created_at), 'YYYY-MM') AS bucket, COUNT(*)
+		FROM worlds
+		WHERE ($1 = '' OR LOWER(title) LIKE '%' || LOWER($1) || '%' OR LOWER(description) LIKE '%' || LOWER($1) || '%')
+		GROUP BY bucket
+		ORDER BY bucket DESC
+	`
+
+	rows, err := r.pool.Query(ctx, query, keyword)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var buckets []models.FacetBucket
+	for rows.Next() {
+		var b models.FacetBucket
+		if err := rows.Scan(&b.Value, &b.Count); err != nil {
+			return nil, err
+		}
+		buckets = append(buckets, b)
+	}
+
+	return buckets, rows.Err()
+}
@@ -0,0 +1,48 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/worlds-search/backend/searchparser"
+)
+
+// SearchByQuery runs a structured searchparser query (field filters,
+// phrases, ranges, negation) against the worlds table, translating the AST
+// to SQL via searchparser.ToSQL. Like SearchWithOptions, only title,
+// description, and created carry a backing column today - tag/user/
+// world_type field filters parse successfully but match everything.
+func (r *WorldsRepository) SearchByQuery(ctx context.Context, query string, limit int) ([]WorldWithSimilarity, error) {
+	node, err := searchparser.Parse(query)
+	if err != nil {
+		return nil, fmt.Errorf("repositories: parsing query: %w", err)
+	}
+
+	where, args := searchparser.ToSQL(node, 0)
+	args = append(args, limit)
+
+	sql := fmt.Sprintf(`
+		SELECT id, title, description, created_at
+		FROM worlds
+		WHERE %s
+		ORDER BY created_at DESC
+		LIMIT $%d
+	`, where, len(args))
+
+	rows, err := r.pool.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var results []WorldWithSimilarity
+	for rows.Next() {
+		var ws WorldWithSimilarity
+		if err := rows.Scan(&ws.World.ID, &ws.World.Title, &ws.World.Description, &ws.World.CreatedAt); err != nil {
+			return nil, err
+		}
+		results = append(results, ws)
+	}
+
+	return results, rows.Err()
+}
@@ -4,14 +4,24 @@ import (
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
 	"github.com/worlds-search/backend/handlers"
+	"github.com/worlds-search/backend/middleware"
+	"github.com/worlds-search/backend/telemetry"
 )
 
 // New creates and configures the Gin router
 func New(
 	searchHandler *handlers.SearchHandler,
 	worldsHandler *handlers.WorldsHandler,
+	adminHandler *handlers.AdminHandler,
 ) *gin.Engine {
-	r := gin.Default()
+	// gin.New() instead of gin.Default(): middleware.RequestLogger takes
+	// over request logging as structured fields under the "http"
+	// subsystem, so we don't want gin's own plain-text Logger() as well.
+	// Recovery() is still required to turn a handler panic into a 500
+	// instead of killing the process.
+	r := gin.New()
+	r.Use(gin.Recovery())
+	r.Use(middleware.RequestLogger())
 
 	// Configure CORS for frontend access
 	r.Use(cors.New(cors.Config{
@@ -27,6 +37,9 @@ func New(
 		c.JSON(200, gin.H{"status": "ok"})
 	})
 
+	// Prometheus scrape endpoint for the search/ranking pipeline's OTel metrics
+	r.GET("/metrics", gin.WrapH(telemetry.Handler()))
+
 	// API routes
 	api := r.Group("/api")
 	{
@@ -45,11 +58,20 @@ func New(
 			// GET /api/search/results - Get search results (worlds list)
 			search.GET("/results", searchHandler.GetResults)
 
+			// POST /api/search/results - Structured search DSL (filters, facets, sort, pagination)
+			search.POST("/results", searchHandler.PostResults)
+
 			// GET /api/search/trending - Get trending keywords
 			search.GET("/trending", searchHandler.GetTrending)
 
 			// GET /api/search/compare - Compare TRGM vs BIGRAM search (Search Modes Lab)
 			search.GET("/compare", searchHandler.HandleSearchCompare)
+
+			// GET /api/search/query - Structured query language (field filters, phrases, ranges, negation)
+			search.GET("/query", searchHandler.GetQueryResults)
+
+			// GET /api/search/parse - Debug endpoint echoing the parsed query AST
+			search.GET("/parse", searchHandler.ParseQuery)
 		}
 
 		// Worlds endpoints
@@ -58,6 +80,19 @@ func New(
 			// GET /api/worlds/:id - Get world details
 			worlds.GET("/:id", worldsHandler.GetWorld)
 		}
+
+		// Admin endpoints
+		admin := api.Group("/admin")
+		{
+			// POST /api/admin/search/model/retrain - Refit the learned ranker model
+			admin.POST("/search/model/retrain", searchHandler.RetrainModel)
+
+			// POST /api/admin/reindex - Start a bulk reindex of worlds into the active SearchBackend
+			admin.POST("/reindex", adminHandler.StartReindex)
+
+			// GET /api/admin/reindex/:id - Poll a reindex job's progress
+			admin.GET("/reindex/:id", adminHandler.GetReindexStatus)
+		}
 	}
 
 	return r
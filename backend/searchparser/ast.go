@@ -0,0 +1,100 @@
+// Package searchparser parses the small query language GET /api/search/query
+// accepts in its q param - field filters (title:foo), quoted phrases,
+// range comparisons, negation, and bare terms - into an AST. ToSQL
+// translates that AST into a Postgres WHERE fragment; the Bleve and
+// Elasticsearch adapters in the backends package do not consume it.
+package searchparser
+
+// NodeType identifies the concrete type of a Node for JSON/debug output
+// (the /api/search/parse endpoint echoes this).
+type NodeType string
+
+const (
+	NodeAnd   NodeType = "and"
+	NodeOr    NodeType = "or"
+	NodeNot   NodeType = "not"
+	NodeField NodeType = "field"
+	NodePhrase NodeType = "phrase"
+	NodeRange NodeType = "range"
+	NodeTerm  NodeType = "term"
+)
+
+// Node is implemented by every AST node kind. Kind() reports the node type
+// without a type switch on the caller's side; JSON output carries the same
+// value through each node's Type field, which the parser sets.
+type Node interface {
+	Kind() NodeType
+}
+
+// AndNode requires every child to match.
+type AndNode struct {
+	Type     NodeType `json:"type"`
+	Children []Node   `json:"children"`
+}
+
+func (n *AndNode) Kind() NodeType { return NodeAnd }
+
+// OrNode requires at least one child to match.
+type OrNode struct {
+	Type     NodeType `json:"type"`
+	Children []Node   `json:"children"`
+}
+
+func (n *OrNode) Kind() NodeType { return NodeOr }
+
+// NotNode negates its single child.
+type NotNode struct {
+	Type  NodeType `json:"type"`
+	Child Node     `json:"child"`
+}
+
+func (n *NotNode) Kind() NodeType { return NodeNot }
+
+// FieldNode is a `field:value` filter, e.g. `title:foo`, `tag:vr`,
+// `user:@alice`. Unknown fields are not rejected at parse time - the
+// consuming backend decides whether to honor or ignore them (the Postgres
+// backend, for instance, treats `tag`/`user` as documented no-ops since
+// the worlds table has no such columns yet).
+type FieldNode struct {
+	Type  NodeType `json:"type"`
+	Field string   `json:"field"`
+	Value Node     `json:"value"`
+}
+
+func (n *FieldNode) Kind() NodeType { return NodeField }
+
+// PhraseNode is an exact quoted phrase, e.g. `description:"exact phrase"`.
+type PhraseNode struct {
+	Type  NodeType `json:"type"`
+	Value string   `json:"value"`
+}
+
+func (n *PhraseNode) Kind() NodeType { return NodePhrase }
+
+// RangeOp is the comparison operator in a RangeNode.
+type RangeOp string
+
+const (
+	RangeGT RangeOp = ">"
+	RangeLT RangeOp = "<"
+	RangeGE RangeOp = ">="
+	RangeLE RangeOp = "<="
+)
+
+// RangeNode is a `field:>value` style comparison, e.g. `created:>2024-01-01`.
+type RangeNode struct {
+	Type  NodeType `json:"type"`
+	Field string   `json:"field"`
+	Op    RangeOp  `json:"op"`
+	Value string   `json:"value"`
+}
+
+func (n *RangeNode) Kind() NodeType { return NodeRange }
+
+// TermNode is a bare keyword with no field/phrase/range syntax.
+type TermNode struct {
+	Type  NodeType `json:"type"`
+	Value string   `json:"value"`
+}
+
+func (n *TermNode) Kind() NodeType { return NodeTerm }
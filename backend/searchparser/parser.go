@@ -0,0 +1,154 @@
+package searchparser
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ParseError reports the offending token and its rune offset into the
+// original query string, so the frontend can underline exactly where
+// parsing went wrong.
+type ParseError struct {
+	Token    string
+	Position int
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("searchparser: invalid token %q at position %d", e.Token, e.Position)
+}
+
+// token is a single whitespace-delimited (quote-aware) chunk of the query
+// plus where it started, for error reporting.
+type token struct {
+	text string
+	pos  int
+}
+
+// Parse parses a raw query string into an AST. Unbalanced quotes and
+// unknown field names degrade gracefully (the trailing/whole text is kept
+// as a literal term or field value) rather than failing the parse -
+// only a genuinely empty field name (e.g. a bare ":foo") is reported as a
+// ParseError, since nothing reasonable can be inferred from it.
+func Parse(query string) (Node, error) {
+	tokens := tokenize(query)
+	clauses := make([]Node, 0, len(tokens))
+
+	i := 0
+	for i < len(tokens) {
+		tok := tokens[i]
+		if strings.EqualFold(tok.text, "OR") && len(clauses) > 0 && i+1 < len(tokens) {
+			left := clauses[len(clauses)-1]
+			right, err := parseClause(tokens[i+1])
+			if err != nil {
+				return nil, err
+			}
+			clauses[len(clauses)-1] = &OrNode{Type: NodeOr, Children: []Node{left, right}}
+			i += 2
+			continue
+		}
+
+		node, err := parseClause(tok)
+		if err != nil {
+			return nil, err
+		}
+		clauses = append(clauses, node)
+		i++
+	}
+
+	if len(clauses) == 0 {
+		return &TermNode{Type: NodeTerm, Value: ""}, nil
+	}
+	if len(clauses) == 1 {
+		return clauses[0], nil
+	}
+	return &AndNode{Type: NodeAnd, Children: clauses}, nil
+}
+
+// tokenize splits query on whitespace, keeping quoted spans (even
+// unbalanced ones, which simply run to the end of the string) as a single
+// token.
+func tokenize(query string) []token {
+	var tokens []token
+	var buf strings.Builder
+	inQuotes := false
+	start := -1
+
+	runes := []rune(query)
+	for i, r := range runes {
+		switch {
+		case r == '"':
+			if start == -1 {
+				start = i
+			}
+			inQuotes = !inQuotes
+			buf.WriteRune(r)
+		case r == ' ' && !inQuotes:
+			if buf.Len() > 0 {
+				tokens = append(tokens, token{text: buf.String(), pos: start})
+				buf.Reset()
+				start = -1
+			}
+		default:
+			if start == -1 {
+				start = i
+			}
+			buf.WriteRune(r)
+		}
+	}
+	if buf.Len() > 0 {
+		tokens = append(tokens, token{text: buf.String(), pos: start})
+	}
+	return tokens
+}
+
+func parseClause(tok token) (Node, error) {
+	text := tok.text
+	if text == "" {
+		return &TermNode{Type: NodeTerm, Value: ""}, nil
+	}
+
+	if strings.HasPrefix(text, "-") && len(text) > 1 {
+		child, err := parseClause(token{text: text[1:], pos: tok.pos + 1})
+		if err != nil {
+			return nil, err
+		}
+		return &NotNode{Type: NodeNot, Child: child}, nil
+	}
+
+	if idx := strings.Index(text, ":"); idx >= 0 {
+		field := text[:idx]
+		rest := text[idx+1:]
+		if field == "" {
+			return nil, &ParseError{Token: text, Position: tok.pos}
+		}
+
+		switch {
+		case strings.HasPrefix(rest, ">="):
+			return &RangeNode{Type: NodeRange, Field: field, Op: RangeGE, Value: rest[2:]}, nil
+		case strings.HasPrefix(rest, "<="):
+			return &RangeNode{Type: NodeRange, Field: field, Op: RangeLE, Value: rest[2:]}, nil
+		case strings.HasPrefix(rest, ">"):
+			return &RangeNode{Type: NodeRange, Field: field, Op: RangeGT, Value: rest[1:]}, nil
+		case strings.HasPrefix(rest, "<"):
+			return &RangeNode{Type: NodeRange, Field: field, Op: RangeLT, Value: rest[1:]}, nil
+		case strings.HasPrefix(rest, `"`):
+			return &FieldNode{Type: NodeField, Field: field, Value: &PhraseNode{Type: NodePhrase, Value: unquote(rest)}}, nil
+		default:
+			return &FieldNode{Type: NodeField, Field: field, Value: &TermNode{Type: NodeTerm, Value: rest}}, nil
+		}
+	}
+
+	if strings.HasPrefix(text, `"`) {
+		return &PhraseNode{Type: NodePhrase, Value: unquote(text)}, nil
+	}
+
+	return &TermNode{Type: NodeTerm, Value: text}, nil
+}
+
+// unquote strips leading/trailing quote marks if present, tolerating an
+// unbalanced (only leading, or only trailing) quote.
+func unquote(s string) string {
+	s = strings.TrimPrefix(s, `"`)
+	s = strings.TrimSuffix(s, `"`)
+	return s
+}
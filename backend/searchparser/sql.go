@@ -0,0 +1,116 @@
+package searchparser
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ToSQL translates an AST into a Postgres WHERE fragment and its
+// positional args, with placeholders numbered starting at argOffset+1 so
+// the caller can splice the fragment into a query that already has its own
+// leading parameters (e.g. $1 = the normalized keyword).
+//
+// Only title/description (bare terms and phrases) and created (range)
+// actually filter anything - tag/user/world_type fields are accepted and
+// echoed back by /api/search/parse but have no backing column yet, so they
+// compile to the literal `TRUE`, the same documented-no-op pattern
+// WorldsRepository.SearchWithOptions uses for filters it can't apply.
+func ToSQL(node Node, argOffset int) (string, []interface{}) {
+	var args []interface{}
+	clause := toSQL(node, &argOffset, &args)
+	return clause, args
+}
+
+func toSQL(node Node, argOffset *int, args *[]interface{}) string {
+	switch n := node.(type) {
+	case *AndNode:
+		return joinClauses(n.Children, "AND", argOffset, args)
+	case *OrNode:
+		return joinClauses(n.Children, "OR", argOffset, args)
+	case *NotNode:
+		return "NOT (" + toSQL(n.Child, argOffset, args) + ")"
+	case *FieldNode:
+		return fieldSQL(n, argOffset, args)
+	case *RangeNode:
+		return rangeSQL(n, argOffset, args)
+	case *PhraseNode:
+		return termSQL(n.Value, argOffset, args)
+	case *TermNode:
+		if n.Value == "" {
+			return "TRUE"
+		}
+		return termSQL(n.Value, argOffset, args)
+	default:
+		return "TRUE"
+	}
+}
+
+func joinClauses(children []Node, op string, argOffset *int, args *[]interface{}) string {
+	if len(children) == 0 {
+		return "TRUE"
+	}
+	parts := make([]string, len(children))
+	for i, c := range children {
+		parts[i] = "(" + toSQL(c, argOffset, args) + ")"
+	}
+	return strings.Join(parts, " "+op+" ")
+}
+
+func nextPlaceholder(argOffset *int, args *[]interface{}, value interface{}) string {
+	*argOffset++
+	*args = append(*args, value)
+	return fmt.Sprintf("$%d", *argOffset)
+}
+
+// termSQL matches a bare term/phrase against title or description.
+func termSQL(value string, argOffset *int, args *[]interface{}) string {
+	placeholder := nextPlaceholder(argOffset, args, "%"+strings.ToLower(value)+"%")
+	return fmt.Sprintf("(lower(title) LIKE %s OR lower(description) LIKE %s)", placeholder, placeholder)
+}
+
+func fieldSQL(n *FieldNode, argOffset *int, args *[]interface{}) string {
+	value := fieldValue(n.Value)
+
+	switch n.Field {
+	case "title":
+		placeholder := nextPlaceholder(argOffset, args, "%"+strings.ToLower(value)+"%")
+		return fmt.Sprintf("lower(title) LIKE %s", placeholder)
+	case "description":
+		placeholder := nextPlaceholder(argOffset, args, "%"+strings.ToLower(value)+"%")
+		return fmt.Sprintf("lower(description) LIKE %s", placeholder)
+	default:
+		// tag, user, world_type, and anything else: documented no-op.
+		return "TRUE"
+	}
+}
+
+func fieldValue(n Node) string {
+	switch v := n.(type) {
+	case *TermNode:
+		return v.Value
+	case *PhraseNode:
+		return v.Value
+	default:
+		return ""
+	}
+}
+
+func rangeSQL(n *RangeNode, argOffset *int, args *[]interface{}) string {
+	if n.Field != "created" {
+		return "TRUE"
+	}
+
+	placeholder := nextPlaceholder(argOffset, args, n.Value)
+	switch n.Op {
+	case RangeGT:
+		return fmt.Sprintf("created_at > %s::timestamptz", placeholder)
+	case RangeGE:
+		return fmt.Sprintf("created_at >= %s::timestamptz", placeholder)
+	case RangeLT:
+		return fmt.Sprintf("created_at < %s::timestamptz", placeholder)
+	case RangeLE:
+		return fmt.Sprintf("created_at <= %s::timestamptz", placeholder)
+	default:
+		return "TRUE"
+	}
+}
@@ -0,0 +1,97 @@
+package services
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// The source* constants identify the four places GetSuggestions pulls
+// candidates from.
+const (
+	sourceUserHistory = "user_history"
+	sourceTrending    = "trending"
+	sourcePrefix      = "prefix"
+	sourceFuzzy       = "fuzzy"
+)
+
+// sourceMetrics accumulates latency/timeout/error counters for one
+// candidate source across requests, so SuggestionsService.SourceStats can
+// report how often a source gets dropped under load rather than just the
+// happy-path latency. No handler exposes SourceStats.
+type sourceMetrics struct {
+	calls          atomic.Uint64
+	errors         atomic.Uint64
+	timeouts       atomic.Uint64
+	totalLatencyUs atomic.Uint64
+}
+
+func (m *sourceMetrics) record(latency time.Duration, err error, timedOut bool) {
+	m.calls.Add(1)
+	m.totalLatencyUs.Add(uint64(latency.Microseconds()))
+	if timedOut {
+		m.timeouts.Add(1)
+	} else if err != nil {
+		m.errors.Add(1)
+	}
+}
+
+// SourceStats is a point-in-time snapshot suitable for exposing on a
+// debug/metrics endpoint.
+type SourceStats struct {
+	Calls        uint64
+	Errors       uint64
+	Timeouts     uint64
+	AvgLatencyMs float64
+}
+
+func (m *sourceMetrics) snapshot() SourceStats {
+	calls := m.calls.Load()
+	stats := SourceStats{
+		Calls:    calls,
+		Errors:   m.errors.Load(),
+		Timeouts: m.timeouts.Load(),
+	}
+	if calls > 0 {
+		stats.AvgLatencyMs = float64(m.totalLatencyUs.Load()) / float64(calls) / 1000.0
+	}
+	return stats
+}
+
+// candidateSourceStats holds one sourceMetrics per candidate source.
+type candidateSourceStats struct {
+	mu       sync.RWMutex
+	bySource map[string]*sourceMetrics
+}
+
+func newCandidateSourceStats() *candidateSourceStats {
+	return &candidateSourceStats{
+		bySource: map[string]*sourceMetrics{
+			sourceUserHistory: {},
+			sourceTrending:    {},
+			sourcePrefix:      {},
+			sourceFuzzy:       {},
+		},
+	}
+}
+
+func (s *candidateSourceStats) record(source string, latency time.Duration, err error, timedOut bool) {
+	s.mu.RLock()
+	m, ok := s.bySource[source]
+	s.mu.RUnlock()
+	if !ok {
+		return
+	}
+	m.record(latency, err, timedOut)
+}
+
+// Snapshot returns a copy of the current stats for every source.
+func (s *candidateSourceStats) Snapshot() map[string]SourceStats {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	out := make(map[string]SourceStats, len(s.bySource))
+	for name, m := range s.bySource {
+		out[name] = m.snapshot()
+	}
+	return out
+}
@@ -2,10 +2,16 @@ package services
 
 import (
 	"context"
+	"errors"
+	"time"
 
 	"github.com/google/uuid"
+	"github.com/worlds-search/backend/backends"
+	"github.com/worlds-search/backend/models"
 	"github.com/worlds-search/backend/repositories"
+	"github.com/worlds-search/backend/telemetry"
 	"github.com/worlds-search/backend/utils"
+	"github.com/worlds-search/backend/utils/concurrency"
 )
 
 // SearchMode represents the search algorithm mode
@@ -15,14 +21,37 @@ const (
 	SearchModeTrgm   SearchMode = "trgm"
 	SearchModeBigram SearchMode = "bigram"
 	SearchModeBoth   SearchMode = "both"
+	SearchModeBleve  SearchMode = "bleve"
+	SearchModeES     SearchMode = "es"
+	SearchModeAll    SearchMode = "all"
+	SearchModeRRF    SearchMode = "rrf"
 )
 
-// CompareWorldResult represents a single world result with score
+const (
+	// compareModeTimeout bounds how long any single mode gets in the "both"
+	// and "all" fan-outs, mirroring SuggestionsService's
+	// perSourceTimeout/overallTimeout split (see deadline_timer.go) but with
+	// a looser budget since the Search Modes Lab is a debug tool, not the
+	// autocomplete hot path.
+	compareModeTimeout = 300 * time.Millisecond
+	// compareOverallTimeout bounds the whole fan-out regardless of how many
+	// modes are still running.
+	compareOverallTimeout = 500 * time.Millisecond
+)
+
+// CompareWorldResult represents a single world result with score. The
+// Rank fields are only populated by SearchModeRRF - they report the
+// 1-based rank (0 if absent) a world held in each source's own ranking
+// before RRF fusion, so the Search Modes Lab UI can show why a world
+// landed where it did.
 type CompareWorldResult struct {
 	ID          uuid.UUID `json:"id"`
 	Title       string    `json:"title"`
 	Description string    `json:"description"`
 	Score       float64   `json:"score"`
+	PrefixRank  int       `json:"prefixRank,omitempty"`
+	TrgmRank    int       `json:"trgmRank,omitempty"`
+	BigramRank  int       `json:"bigramRank,omitempty"`
 }
 
 // SearchCompareResponse is the response for search compare API
@@ -31,28 +60,52 @@ type SearchCompareResponse struct {
 	Mode          SearchMode           `json:"mode"`
 	TrgmResults   []CompareWorldResult `json:"trgmResults"`
 	BigramResults []CompareWorldResult `json:"bigramResults"`
+	BleveResults  []CompareWorldResult `json:"bleveResults,omitempty"`
+	ESResults     []CompareWorldResult `json:"esResults,omitempty"`
+	RRFResults    []CompareWorldResult `json:"rrfResults,omitempty"`
+	// ModeStatus carries one entry per mode that "both"/"all" fanned out
+	// to and that didn't come back clean - a real backend error, or a
+	// timeout against compareModeTimeout/compareOverallTimeout. A mode
+	// with no entry here either wasn't part of the fan-out or completed
+	// successfully (including "completed with zero hits", which is not
+	// an error). The UI renders whichever *Results fields did come back,
+	// plus this map, instead of failing the whole request.
+	ModeStatus map[SearchMode]CompareModeStatus `json:"modeStatus,omitempty"`
+}
+
+// CompareModeStatus reports why a single mode in a "both"/"all" fan-out
+// didn't produce results.
+type CompareModeStatus struct {
+	Error    string `json:"error"`
+	TimedOut bool   `json:"timedOut"`
 }
 
 // CompareService handles search comparison operations
 type CompareService struct {
-	worldsRepo *repositories.WorldsRepository
+	worldsRepo     *repositories.WorldsRepository
+	bleveBackend   backends.SearchBackend
+	elasticBackend backends.SearchBackend
 }
 
-// NewCompareService creates a new CompareService
-func NewCompareService(worldsRepo *repositories.WorldsRepository) *CompareService {
+// NewCompareService creates a new CompareService. bleveBackend and
+// elasticBackend are optional (may be nil) - the lab simply omits those
+// modes from SearchCompare if their backend wasn't configured.
+func NewCompareService(worldsRepo *repositories.WorldsRepository, bleveBackend, elasticBackend backends.SearchBackend) *CompareService {
 	return &CompareService{
-		worldsRepo: worldsRepo,
+		worldsRepo:     worldsRepo,
+		bleveBackend:   bleveBackend,
+		elasticBackend: elasticBackend,
 	}
 }
 
 // SearchTrgm performs TRGM-only search
-func (s *CompareService) SearchTrgm(ctx context.Context, keyword string, limit int) ([]CompareWorldResult, error) {
+func (s *CompareService) SearchTrgm(ctx context.Context, keyword string, limit int, fuzzyMode models.FuzzyMode) ([]CompareWorldResult, error) {
 	normalizedKeyword := utils.NormalizeKeyword(keyword)
 	if normalizedKeyword == "" {
 		return []CompareWorldResult{}, nil
 	}
 
-	results, err := s.worldsRepo.FindByTitleTrgm(ctx, normalizedKeyword, limit)
+	results, err := s.worldsRepo.FindByTitleTrgm(ctx, normalizedKeyword, limit, fuzzyMode)
 	if err != nil {
 		return nil, err
 	}
@@ -61,13 +114,13 @@ func (s *CompareService) SearchTrgm(ctx context.Context, keyword string, limit i
 }
 
 // SearchBigram performs BIGRAM-only search
-func (s *CompareService) SearchBigram(ctx context.Context, keyword string, limit int) ([]CompareWorldResult, error) {
+func (s *CompareService) SearchBigram(ctx context.Context, keyword string, limit int, fuzzyMode models.FuzzyMode) ([]CompareWorldResult, error) {
 	normalizedKeyword := utils.NormalizeKeyword(keyword)
 	if normalizedKeyword == "" {
 		return []CompareWorldResult{}, nil
 	}
 
-	results, err := s.worldsRepo.FindByTitleBigram(ctx, normalizedKeyword, limit)
+	results, err := s.worldsRepo.FindByTitleBigram(ctx, normalizedKeyword, limit, fuzzyMode)
 	if err != nil {
 		return nil, err
 	}
@@ -75,30 +128,171 @@ func (s *CompareService) SearchBigram(ctx context.Context, keyword string, limit
 	return s.convertToCompareResults(results), nil
 }
 
-// SearchBoth performs both TRGM and BIGRAM searches
-func (s *CompareService) SearchBoth(ctx context.Context, keyword string, limit int) (trgmResults []CompareWorldResult, bigramResults []CompareWorldResult, err error) {
+// SearchRRF performs a Reciprocal Rank Fusion search, combining prefix,
+// trgm, and bigram rankings in a single query (see
+// WorldsRepository.SearchRRF). k overrides the RRF damping constant; pass
+// 0 to use WorldsRepository's default.
+func (s *CompareService) SearchRRF(ctx context.Context, keyword string, limit int, fuzzyMode models.FuzzyMode, k float64) ([]CompareWorldResult, error) {
 	normalizedKeyword := utils.NormalizeKeyword(keyword)
 	if normalizedKeyword == "" {
-		return []CompareWorldResult{}, []CompareWorldResult{}, nil
+		return []CompareWorldResult{}, nil
 	}
 
-	// Run TRGM search
-	trgm, err := s.worldsRepo.FindByTitleTrgm(ctx, normalizedKeyword, limit)
+	results, err := s.worldsRepo.SearchRRF(ctx, normalizedKeyword, limit, fuzzyMode, k)
 	if err != nil {
-		return nil, nil, err
+		return nil, err
+	}
+
+	return s.convertToCompareResults(results), nil
+}
+
+// modeFetchers are the per-mode search calls gatherModes can fan out
+// across. Only the modes requested by the caller are included, so "both"
+// fans out over two goroutines and "all" over up to five. k is the RRF
+// damping override (0 = default), used only by the rrf fetcher.
+func (s *CompareService) modeFetchers(keyword string, limit int, fuzzyMode models.FuzzyMode, k float64) map[SearchMode]func(context.Context) ([]CompareWorldResult, error) {
+	fetchers := map[SearchMode]func(context.Context) ([]CompareWorldResult, error){
+		SearchModeTrgm:   func(ctx context.Context) ([]CompareWorldResult, error) { return s.SearchTrgm(ctx, keyword, limit, fuzzyMode) },
+		SearchModeBigram: func(ctx context.Context) ([]CompareWorldResult, error) { return s.SearchBigram(ctx, keyword, limit, fuzzyMode) },
+		SearchModeRRF:    func(ctx context.Context) ([]CompareWorldResult, error) { return s.SearchRRF(ctx, keyword, limit, fuzzyMode, k) },
+	}
+	if s.bleveBackend != nil {
+		fetchers[SearchModeBleve] = func(ctx context.Context) ([]CompareWorldResult, error) { return s.SearchBleve(ctx, keyword, limit) }
 	}
+	if s.elasticBackend != nil {
+		fetchers[SearchModeES] = func(ctx context.Context) ([]CompareWorldResult, error) { return s.SearchES(ctx, keyword, limit) }
+	}
+	return fetchers
+}
 
-	// Run BIGRAM search
-	bigram, err := s.worldsRepo.FindByTitleBigram(ctx, normalizedKeyword, limit)
+// selectModes filters modes down to requested, preserving modes' order and
+// dropping any name requested is missing from (an unconfigured backend, or
+// a typo). A nil/empty requested means "use modes unchanged" - the default
+// fan-out set for "both"/"all".
+func selectModes(modes []SearchMode, requested []SearchMode) []SearchMode {
+	if len(requested) == 0 {
+		return modes
+	}
+	want := make(map[SearchMode]bool, len(requested))
+	for _, m := range requested {
+		want[m] = true
+	}
+	selected := make([]SearchMode, 0, len(modes))
+	for _, m := range modes {
+		if want[m] {
+			selected = append(selected, m)
+		}
+	}
+	return selected
+}
+
+// gatherModes runs each of the given modes concurrently via
+// utils/concurrency.ForEachJob, each under its own
+// compareModeTimeout-bounded context and all bounded by
+// compareOverallTimeout, and returns whatever completed along with a
+// per-mode status for anything that didn't. A mode's job never returns an
+// error to ForEachJob itself - a failed or timed-out mode is recorded in
+// status and the job returns nil, so one bad backend can't cancel its
+// siblings (see ForEachJob's doc comment on that distinction).
+func (s *CompareService) gatherModes(ctx context.Context, modes []SearchMode, fetchers map[SearchMode]func(context.Context) ([]CompareWorldResult, error)) (map[SearchMode][]CompareWorldResult, map[SearchMode]CompareModeStatus) {
+	overallCtx, cancel := context.WithTimeout(ctx, compareOverallTimeout)
+	defer cancel()
+
+	resultsByIdx := make([][]CompareWorldResult, len(modes))
+	statusByIdx := make([]CompareModeStatus, len(modes))
+	hasStatus := make([]bool, len(modes))
+
+	_ = concurrency.ForEachJob(overallCtx, len(modes), len(modes), func(jobCtx context.Context, idx int) error {
+		mode := modes[idx]
+		fetch, ok := fetchers[mode]
+		if !ok {
+			return nil
+		}
+
+		start := time.Now()
+		mctx, mcancel := context.WithTimeout(jobCtx, compareModeTimeout)
+		defer mcancel()
+
+		res, err := fetch(mctx)
+		telemetry.RecordLatency(ctx, string(mode), time.Since(start).Seconds(), nil)
+
+		if err != nil {
+			hasStatus[idx] = true
+			statusByIdx[idx] = CompareModeStatus{
+				Error:    err.Error(),
+				TimedOut: errors.Is(err, context.DeadlineExceeded),
+			}
+			return nil
+		}
+		resultsByIdx[idx] = res
+		return nil
+	})
+
+	out := make(map[SearchMode][]CompareWorldResult, len(modes))
+	status := make(map[SearchMode]CompareModeStatus)
+	for i, mode := range modes {
+		if resultsByIdx[i] != nil {
+			out[mode] = resultsByIdx[i]
+		}
+		if hasStatus[i] {
+			status[mode] = statusByIdx[i]
+		}
+	}
+	return out, status
+}
+
+// SearchBleve performs a Bleve-only search, for side-by-side comparison
+// against the Postgres modes in the Search Modes Lab.
+func (s *CompareService) SearchBleve(ctx context.Context, keyword string, limit int) ([]CompareWorldResult, error) {
+	if s.bleveBackend == nil {
+		return nil, backends.ErrUnsupported
+	}
+
+	normalizedKeyword := utils.NormalizeKeyword(keyword)
+	if normalizedKeyword == "" {
+		return []CompareWorldResult{}, nil
+	}
+
+	matches, err := s.bleveBackend.FullTextSearch(ctx, normalizedKeyword, limit)
 	if err != nil {
-		return nil, nil, err
+		return nil, err
 	}
 
-	return s.convertToCompareResults(trgm), s.convertToCompareResults(bigram), nil
+	return s.convertMatches(matches), nil
 }
 
-// SearchCompare performs search based on mode and returns formatted response
-func (s *CompareService) SearchCompare(ctx context.Context, keyword string, mode SearchMode, limit int) (*SearchCompareResponse, error) {
+// SearchES performs an Elasticsearch-only search.
+func (s *CompareService) SearchES(ctx context.Context, keyword string, limit int) ([]CompareWorldResult, error) {
+	if s.elasticBackend == nil {
+		return nil, backends.ErrUnsupported
+	}
+
+	normalizedKeyword := utils.NormalizeKeyword(keyword)
+	if normalizedKeyword == "" {
+		return []CompareWorldResult{}, nil
+	}
+
+	matches, err := s.elasticBackend.FullTextSearch(ctx, normalizedKeyword, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.convertMatches(matches), nil
+}
+
+// SearchCompare performs search based on mode and returns formatted
+// response. fuzzyMode is only honored by the trgm/bigram/rrf modes -
+// bleve/es don't expose a per-query fuzziness knob yet. "both" and "all"
+// fan out across their constituent modes concurrently via gatherModes
+// instead of running them one after another. k overrides the RRF damping
+// constant for SearchModeRRF and the rrf leg of "all" (0 = default); it's
+// ignored by every other mode. backends, when non-empty, restricts "all" to that subset of
+// modes (e.g. just "trgm,bleve") instead of every backend the service has
+// configured; it's ignored by every mode other than "all".
+func (s *CompareService) SearchCompare(ctx context.Context, keyword string, mode SearchMode, limit int, fuzzyMode models.FuzzyMode, k float64, backends []SearchMode) (*SearchCompareResponse, error) {
+	ctx, span := telemetry.StartSpan(ctx, "search_compare")
+	defer span.End()
+
 	response := &SearchCompareResponse{
 		Keyword:       keyword,
 		Mode:          mode,
@@ -108,32 +302,104 @@ func (s *CompareService) SearchCompare(ctx context.Context, keyword string, mode
 
 	switch mode {
 	case SearchModeTrgm:
-		results, err := s.SearchTrgm(ctx, keyword, limit)
+		start := time.Now()
+		results, err := s.SearchTrgm(ctx, keyword, limit, fuzzyMode)
+		telemetry.RecordLatency(ctx, string(SearchModeTrgm), time.Since(start).Seconds(), nil)
 		if err != nil {
 			return nil, err
 		}
 		response.TrgmResults = results
 
 	case SearchModeBigram:
-		results, err := s.SearchBigram(ctx, keyword, limit)
+		start := time.Now()
+		results, err := s.SearchBigram(ctx, keyword, limit, fuzzyMode)
+		telemetry.RecordLatency(ctx, string(SearchModeBigram), time.Since(start).Seconds(), nil)
 		if err != nil {
 			return nil, err
 		}
 		response.BigramResults = results
 
 	case SearchModeBoth:
-		trgm, bigram, err := s.SearchBoth(ctx, keyword, limit)
+		fetchers := s.modeFetchers(keyword, limit, fuzzyMode, k)
+		out, status := s.gatherModes(ctx, []SearchMode{SearchModeTrgm, SearchModeBigram}, fetchers)
+		response.TrgmResults = withDefault(out[SearchModeTrgm])
+		response.BigramResults = withDefault(out[SearchModeBigram])
+		response.ModeStatus = status
+
+	case SearchModeAll:
+		fetchers := s.modeFetchers(keyword, limit, fuzzyMode, k)
+		modes := make([]SearchMode, 0, len(fetchers))
+		for m := range fetchers {
+			modes = append(modes, m)
+		}
+		modes = selectModes(modes, backends)
+		out, status := s.gatherModes(ctx, modes, fetchers)
+		response.TrgmResults = withDefault(out[SearchModeTrgm])
+		response.BigramResults = withDefault(out[SearchModeBigram])
+		response.BleveResults = out[SearchModeBleve]
+		response.ESResults = out[SearchModeES]
+		response.RRFResults = out[SearchModeRRF]
+		response.ModeStatus = status
+
+	case SearchModeBleve:
+		start := time.Now()
+		results, err := s.SearchBleve(ctx, keyword, limit)
+		telemetry.RecordLatency(ctx, string(SearchModeBleve), time.Since(start).Seconds(), nil)
+		if err != nil {
+			return nil, err
+		}
+		response.BleveResults = results
+
+	case SearchModeES:
+		start := time.Now()
+		results, err := s.SearchES(ctx, keyword, limit)
+		telemetry.RecordLatency(ctx, string(SearchModeES), time.Since(start).Seconds(), nil)
+		if err != nil {
+			return nil, err
+		}
+		response.ESResults = results
+
+	case SearchModeRRF:
+		start := time.Now()
+		results, err := s.SearchRRF(ctx, keyword, limit, fuzzyMode, k)
+		telemetry.RecordLatency(ctx, string(SearchModeRRF), time.Since(start).Seconds(), nil)
 		if err != nil {
 			return nil, err
 		}
-		response.TrgmResults = trgm
-		response.BigramResults = bigram
+		response.RRFResults = results
 	}
 
 	return response, nil
 }
 
-// convertToCompareResults converts WorldWithSimilarity slice to CompareWorldResult slice
+// withDefault returns results, or an empty (non-nil) slice if the mode was
+// dropped from gatherModes - the JSON response always has trgmResults/
+// bigramResults present, even when "all"/"both" came back partial.
+func withDefault(results []CompareWorldResult) []CompareWorldResult {
+	if results == nil {
+		return []CompareWorldResult{}
+	}
+	return results
+}
+
+// convertMatches converts a backends.Match slice (Bleve/Elasticsearch) to
+// CompareWorldResult slice.
+func (s *CompareService) convertMatches(matches []backends.Match) []CompareWorldResult {
+	compareResults := make([]CompareWorldResult, len(matches))
+	for i, m := range matches {
+		compareResults[i] = CompareWorldResult{
+			ID:          m.World.ID,
+			Title:       m.World.Title,
+			Description: utils.TruncateString(m.World.Description, 200),
+			Score:       m.Score,
+		}
+	}
+	return compareResults
+}
+
+// convertToCompareResults converts WorldWithSimilarity slice to
+// CompareWorldResult slice. PrefixRank/TrgmRank/BigramRank pass straight
+// through - they're only ever non-zero when results came from SearchRRF.
 func (s *CompareService) convertToCompareResults(results []repositories.WorldWithSimilarity) []CompareWorldResult {
 	compareResults := make([]CompareWorldResult, len(results))
 	for i, ws := range results {
@@ -142,6 +408,9 @@ func (s *CompareService) convertToCompareResults(results []repositories.WorldWit
 			Title:       ws.World.Title,
 			Description: utils.TruncateString(ws.World.Description, 200),
 			Score:       ws.Similarity,
+			PrefixRank:  ws.PrefixRank,
+			TrgmRank:    ws.TrgmRank,
+			BigramRank:  ws.BigramRank,
 		}
 	}
 	return compareResults
@@ -0,0 +1,31 @@
+package services
+
+import (
+	"context"
+
+	"github.com/worlds-search/backend/models"
+	"github.com/worlds-search/backend/repositories"
+)
+
+// CTRFeatureProvider supplies RankingService with a smoothed
+// click-through-rate feature per candidate, computed in one batched
+// round-trip rather than one query per candidate.
+type CTRFeatureProvider struct {
+	logsRepo *repositories.LogsRepository
+}
+
+// NewCTRFeatureProvider creates a new CTRFeatureProvider.
+func NewCTRFeatureProvider(logsRepo *repositories.LogsRepository) *CTRFeatureProvider {
+	return &CTRFeatureProvider{logsRepo: logsRepo}
+}
+
+// GetScores returns the smoothed CTR for every candidate, keyed by
+// (candidate.Text, candidate.Type) so RankingService can look it up
+// without a second pass over the repository.
+func (p *CTRFeatureProvider) GetScores(ctx context.Context, candidates []models.SuggestionCandidate) (map[repositories.SuggestionKey]float64, error) {
+	keys := make([]repositories.SuggestionKey, len(candidates))
+	for i, c := range candidates {
+		keys[i] = repositories.SuggestionKey{Suggestion: c.Text, SuggestionType: c.Type}
+	}
+	return p.logsRepo.GetSmoothedCTRBatch(ctx, keys)
+}
@@ -0,0 +1,25 @@
+package services
+
+import "time"
+
+// deadlineTimer fires a dedicated cancellation channel after d elapses,
+// via time.AfterFunc rather than a goroutine blocked on time.After. It
+// bounds the whole candidate fan-out in GetSuggestions (overallTimeout);
+// each candidate source gets its own perSourceTimeout via
+// context.WithTimeout instead.
+type deadlineTimer struct {
+	C     chan struct{}
+	timer *time.Timer
+}
+
+// newDeadlineTimer starts a timer that closes C after d.
+func newDeadlineTimer(d time.Duration) *deadlineTimer {
+	dt := &deadlineTimer{C: make(chan struct{})}
+	dt.timer = time.AfterFunc(d, func() { close(dt.C) })
+	return dt
+}
+
+// Stop cancels the timer. Safe to call after it has already fired.
+func (dt *deadlineTimer) Stop() {
+	dt.timer.Stop()
+}
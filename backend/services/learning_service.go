@@ -0,0 +1,186 @@
+package services
+
+import (
+	"context"
+	"math"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/worlds-search/backend/logging"
+	"github.com/worlds-search/backend/models"
+	"github.com/worlds-search/backend/repositories"
+)
+
+const (
+	// trainingLookback bounds how much impression history a retrain pass
+	// scans.
+	trainingLookback = 14 * 24 * time.Hour
+	trainingRowLimit = 50_000
+
+	sgdLearningRate = 0.01
+	sgdL2Reg        = 0.0001
+	sgdEpochs       = 3
+)
+
+// LearningService periodically refits RankerModel from implicit feedback
+// (search_impressions joined with search_clicks) and keeps the latest
+// model available in memory for RankingService to read without a DB round
+// trip per request.
+//
+// Only the features reconstructable well after the fact - prefix match,
+// pg_trgm fuzzy similarity, and smoothed CTR - are actually fit by SGD
+// below. PersonalFreq and TrendingZ depend on state (the user's history,
+// the trending ZSET) at the moment the suggestion was shown, which isn't
+// preserved by the impression log, so those two weights are carried over
+// from the previous model untouched rather than learned from stale
+// context.
+type LearningService struct {
+	logsRepo  *repositories.LogsRepository
+	modelRepo *repositories.RankerModelRepository
+
+	current atomic.Pointer[models.RankerModel]
+}
+
+// NewLearningService creates a new LearningService, loading the active
+// model from Postgres if one has been trained before, or falling back to
+// DefaultRankerModel otherwise.
+func NewLearningService(ctx context.Context, logsRepo *repositories.LogsRepository, modelRepo *repositories.RankerModelRepository) *LearningService {
+	s := &LearningService{logsRepo: logsRepo, modelRepo: modelRepo}
+
+	// Any error here (no rows yet, or a transient DB failure) falls back
+	// to the default weights - ranking runs un-learned until the next
+	// successful retrain rather than blocking startup.
+	model, err := modelRepo.GetActiveModel(ctx)
+	if err != nil {
+		d := models.DefaultRankerModel()
+		model = &d
+	}
+	s.current.Store(model)
+	return s
+}
+
+// CurrentModel returns the most recently loaded or trained model.
+func (s *LearningService) CurrentModel() models.RankerModel {
+	return *s.current.Load()
+}
+
+// StartPeriodicRetrain runs Retrain every interval until ctx is canceled,
+// logging (but not propagating) failures so a bad retrain pass doesn't
+// take down the server.
+func (s *LearningService) StartPeriodicRetrain(ctx context.Context, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if _, err := s.Retrain(ctx); err != nil {
+					logging.For("learning").Warn("periodic retrain failed", "err", err)
+				}
+			}
+		}
+	}()
+}
+
+// Retrain fits a new RankerModel from recent impressions/clicks and
+// persists it as the active model. Labels are corrected for position bias
+// using the standard 1/log2(2+pos) examination-probability estimate, then
+// fit via online SGD logistic regression with L2 regularization.
+func (s *LearningService) Retrain(ctx context.Context) (models.RankerModel, error) {
+	prev := s.CurrentModel()
+
+	examples, err := s.logsRepo.GetTrainingExamples(ctx, time.Now().Add(-trainingLookback), trainingRowLimit)
+	if err != nil {
+		return prev, err
+	}
+	if len(examples) == 0 {
+		return prev, nil
+	}
+
+	ctrScores, err := s.smoothedCTRFor(ctx, examples)
+	if err != nil {
+		return prev, err
+	}
+
+	prefixWeight, fuzzyWeight, ctrWeight := fitWeights(examples, ctrScores, prev.PrefixWeight, prev.FuzzyWeight, prev.CTRWeight)
+
+	next := models.RankerModel{
+		Version:        prev.Version + 1,
+		PrefixWeight:   prefixWeight,
+		PersonalWeight: prev.PersonalWeight,
+		TrendingWeight: prev.TrendingWeight,
+		FuzzyWeight:    fuzzyWeight,
+		CTRWeight:      ctrWeight,
+		TrainedAt:      time.Now(),
+		TrainingRows:   len(examples),
+	}
+
+	if err := s.modelRepo.SaveModel(ctx, next); err != nil {
+		return prev, err
+	}
+	s.current.Store(&next)
+	return next, nil
+}
+
+func (s *LearningService) smoothedCTRFor(ctx context.Context, examples []repositories.TrainingExample) (map[repositories.SuggestionKey]float64, error) {
+	seen := make(map[repositories.SuggestionKey]bool)
+	keys := make([]repositories.SuggestionKey, 0, len(examples))
+	for _, ex := range examples {
+		k := repositories.SuggestionKey{Suggestion: ex.Suggestion, SuggestionType: ex.SuggestionType}
+		if !seen[k] {
+			seen[k] = true
+			keys = append(keys, k)
+		}
+	}
+	return s.logsRepo.GetSmoothedCTRBatch(ctx, keys)
+}
+
+// positionExaminationProbability is the standard implicit-feedback
+// discount: a suggestion shown further down the list is less likely to
+// have been looked at at all, regardless of relevance, so a click lower
+// in the list is stronger positive evidence than one at the top. fitWeights
+// divides each click label by this probability; non-click labels stay 0.
+func positionExaminationProbability(position int) float64 {
+	return 1.0 / math.Log2(2+float64(position))
+}
+
+// fitWeights runs a few epochs of online SGD logistic regression over
+// [prefixFeature, fuzzyFeature, ctrFeature] -> position-bias-corrected
+// click label, starting from the previous model's weights.
+func fitWeights(examples []repositories.TrainingExample, ctrScores map[repositories.SuggestionKey]float64, initPrefixWeight, initFuzzyWeight, initCTRWeight float64) (float64, float64, float64) {
+	// The learned weights operate on the same 0-100/0-10/0-50 scale as the
+	// static formula, so normalize the raw features by the same factor
+	// computeScores uses before blending them into FinalScore.
+	wPrefix := initPrefixWeight / 100.0
+	wFuzzy := initFuzzyWeight / 10.0
+	wCTR := initCTRWeight / 50.0
+
+	for epoch := 0; epoch < sgdEpochs; epoch++ {
+		for _, ex := range examples {
+			prefixFeature := 0.0
+			if strings.HasPrefix(strings.ToLower(ex.Suggestion), ex.NormalizedKeyword) {
+				prefixFeature = 1.0
+			}
+			fuzzyFeature := ex.FuzzySim
+			ctrFeature := ctrScores[repositories.SuggestionKey{Suggestion: ex.Suggestion, SuggestionType: ex.SuggestionType}]
+
+			label := 0.0
+			if ex.Clicked {
+				label = 1.0 / positionExaminationProbability(ex.Position)
+			}
+
+			z := prefixFeature*wPrefix + fuzzyFeature*wFuzzy + ctrFeature*wCTR
+			pred := 1.0 / (1.0 + math.Exp(-z))
+			gradient := pred - label
+
+			wPrefix -= sgdLearningRate * (gradient*prefixFeature + sgdL2Reg*wPrefix)
+			wFuzzy -= sgdLearningRate * (gradient*fuzzyFeature + sgdL2Reg*wFuzzy)
+			wCTR -= sgdLearningRate * (gradient*ctrFeature + sgdL2Reg*wCTR)
+		}
+	}
+
+	return wPrefix * 100.0, wFuzzy * 10.0, wCTR * 50.0
+}
@@ -2,14 +2,24 @@ package services
 
 import (
 	"context"
+	"hash/fnv"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/worlds-search/backend/models"
 	"github.com/worlds-search/backend/repositories"
+	"github.com/worlds-search/backend/telemetry"
 )
 
+// abStaticPercent is the fraction of traffic (by userID bucket) kept on
+// the static hand-tuned formula instead of the learned model, so CTR@k and
+// MRR can be compared offline between the two. Anonymous (nil userID)
+// traffic always uses the static formula since there's nothing stable to
+// bucket on.
+const abStaticPercent = 10
+
 // ============================================================
 // RANKING SERVICE
 // ============================================================
@@ -18,28 +28,53 @@ import (
 //
 //   final_score = prefix_score + personal_score + trending_score + fuzzy_score + ctr_score
 //
-// Score weights and priorities:
-//   - prefix_score:   Strong signal (100 for prefix match, 50 for contains)
-//   - personal_score: Strong signal (frequency * 20)
-//   - trending_score: Medium signal (redis_score * 1.0)
-//   - fuzzy_score:    Weak signal (similarity * 10)
-//   - ctr_score:      Refinement signal (ctr * 50)
+// where each term is a feature (prefix match, personal frequency, trending
+// z-score, fuzzy similarity, smoothed CTR) times a per-feature weight from
+// a models.RankerModel. Signed-in users outside the abStaticPercent bucket
+// get whatever weights LearningService last fit via online SGD against
+// implicit click feedback; that bucket and anonymous traffic keep
+// models.DefaultRankerModel, the original hand-tuned weights (100/50 for
+// prefix, 20x for personal, 10x for fuzzy, 50x for CTR), so the two can be
+// compared via offline CTR@k and MRR.
+//
+// ctr_score uses the lower bound of the Wilson score interval rather than
+// raw clicks/impressions so that a suggestion with 1/1 clicks doesn't
+// outrank one with 950/1000 - see CTRFeatureProvider.
 // ============================================================
 
 // RankingService handles scoring and ranking of suggestions
 type RankingService struct {
-	searchRepo *repositories.SearchRepository
-	logsRepo   *repositories.LogsRepository
+	searchRepo      *repositories.SearchRepository
+	logsRepo        *repositories.LogsRepository
+	ctrProvider     *CTRFeatureProvider
+	learningService *LearningService
 }
 
-// NewRankingService creates a new RankingService
-func NewRankingService(searchRepo *repositories.SearchRepository, logsRepo *repositories.LogsRepository) *RankingService {
+// NewRankingService creates a new RankingService. learningService may be
+// nil, in which case RankSuggestions always uses the static formula - this
+// keeps the learned ranker opt-in rather than required to boot the
+// service.
+func NewRankingService(searchRepo *repositories.SearchRepository, logsRepo *repositories.LogsRepository, learningService *LearningService) *RankingService {
 	return &RankingService{
-		searchRepo: searchRepo,
-		logsRepo:   logsRepo,
+		searchRepo:      searchRepo,
+		logsRepo:        logsRepo,
+		ctrProvider:     NewCTRFeatureProvider(logsRepo),
+		learningService: learningService,
 	}
 }
 
+// usesLearnedModel decides the A/B bucket for userID. Bucketing is stable
+// per user (hash of the UUID mod 100) so the same user consistently lands
+// on the same arm across requests instead of flapping every call.
+func usesLearnedModel(userID *uuid.UUID) bool {
+	if userID == nil {
+		return false
+	}
+	h := fnv.New32a()
+	_, _ = h.Write(userID[:])
+	return h.Sum32()%100 >= abStaticPercent
+}
+
 // ScoredSuggestion is a suggestion with its computed scores
 type ScoredSuggestion struct {
 	Candidate models.SuggestionCandidate
@@ -61,11 +96,37 @@ func (s *RankingService) RankSuggestions(
 	normalizedQuery string,
 	userID *uuid.UUID,
 	trendingKeywords map[string]float64,
+	fuzzyMode models.FuzzyMode,
 ) ([]ScoredSuggestion, error) {
+	ctx, span := telemetry.StartSpan(ctx, "rank_suggestions")
+	defer span.End()
+	start := time.Now()
+	defer func() { telemetry.RecordLatency(ctx, "rank", time.Since(start).Seconds(), userID) }()
+
+	// Fetch CTR for every candidate in one batched, cached round-trip
+	// instead of a query per candidate inside computeScores.
+	ctrCtx, ctrSpan := telemetry.StartSpan(ctx, "ctr_lookup")
+	ctrStart := time.Now()
+	ctrScores, err := s.ctrProvider.GetScores(ctrCtx, candidates)
+	telemetry.RecordLatency(ctrCtx, "ctr_lookup", time.Since(ctrStart).Seconds(), userID)
+	ctrSpan.End()
+	if err != nil {
+		ctrScores = map[repositories.SuggestionKey]float64{}
+	}
+
+	// A/B: signed-in users outside the abStaticPercent bucket get whatever
+	// weights LearningService last trained; that bucket and anonymous
+	// traffic stay on the static hand-tuned formula, so the two can be
+	// compared via offline CTR@k/MRR.
+	weights := models.DefaultRankerModel()
+	if s.learningService != nil && usesLearnedModel(userID) {
+		weights = s.learningService.CurrentModel()
+	}
+
 	scored := make([]ScoredSuggestion, 0, len(candidates))
 
 	for _, candidate := range candidates {
-		scores, err := s.computeScores(ctx, candidate, normalizedQuery, userID, trendingKeywords)
+		scores, err := s.computeScores(ctx, candidate, normalizedQuery, userID, trendingKeywords, ctrScores, weights, fuzzyMode)
 		if err != nil {
 			// Log error but continue with other candidates
 			continue
@@ -82,40 +143,53 @@ func (s *RankingService) RankSuggestions(
 		return scored[i].Scores.FinalScore > scored[j].Scores.FinalScore
 	})
 
+	// Counts which candidate source each ranked suggestion came from, so
+	// source mix can be compared against the A/B ranker bucket over time.
+	// DeduplicateSuggestions/the top-N cut happen in the caller, so this
+	// reflects the full ranked set rather than only what's ultimately shown.
+	for _, ss := range scored {
+		telemetry.RecordSourceContribution(ctx, ss.Candidate.Source, userID)
+	}
+
 	return scored, nil
 }
 
-// computeScores calculates all score components for a single candidate
+// computeScores calculates all score components for a single candidate,
+// blending features with weights - either the static defaults or whatever
+// LearningService last trained, per the caller's A/B bucket.
 func (s *RankingService) computeScores(
 	ctx context.Context,
 	candidate models.SuggestionCandidate,
 	normalizedQuery string,
 	userID *uuid.UUID,
 	trendingKeywords map[string]float64,
+	ctrScores map[repositories.SuggestionKey]float64,
+	weights models.RankerModel,
+	fuzzyMode models.FuzzyMode,
 ) (models.SuggestionScores, error) {
 	var scores models.SuggestionScores
 
 	// ============================================================
 	// 1. PREFIX SCORE
 	// ============================================================
-	// If suggestion text starts with normalized_query: prefix_score = 100
-	// Else if suggestion text contains normalized_query at a later position: prefix_score = 50
-	// Else: prefix_score = 0
+	// If suggestion text starts with normalized_query: prefix_feature = 1.0
+	// Else if suggestion text contains normalized_query at a later position: prefix_feature = 0.5
+	// Else: prefix_feature = 0
 	normalizedText := strings.ToLower(candidate.Text)
+	prefixFeature := 0.0
 	if strings.HasPrefix(normalizedText, normalizedQuery) {
-		scores.PrefixScore = 100.0
+		prefixFeature = 1.0
 	} else if strings.Contains(normalizedText, normalizedQuery) {
-		scores.PrefixScore = 50.0
-	} else {
-		scores.PrefixScore = 0.0
+		prefixFeature = 0.5
 	}
+	scores.PrefixScore = prefixFeature * weights.PrefixWeight
 
 	// ============================================================
 	// 2. PERSONAL SCORE
 	// ============================================================
 	// For keyword suggestions: freq = number of times user searched this keyword
 	// For world suggestions: count how many times user clicked this world
-	// personal_score = freq * 20
+	personalFreq := 0.0
 	if userID != nil {
 		if candidate.Type == models.SuggestionTypeKeyword {
 			// Get frequency from user's search history
@@ -123,7 +197,7 @@ func (s *RankingService) computeScores(
 			if err == nil {
 				for _, kf := range keywords {
 					if strings.ToLower(kf.Keyword) == normalizedText {
-						scores.PersonalScore = float64(kf.Frequency) * 20.0
+						personalFreq = float64(kf.Frequency)
 						break
 					}
 				}
@@ -132,57 +206,65 @@ func (s *RankingService) computeScores(
 			// Get click count for this world
 			count, err := s.searchRepo.GetUserWorldClickCount(ctx, *userID, *candidate.WorldID, normalizedQuery)
 			if err == nil {
-				scores.PersonalScore = float64(count) * 20.0
+				personalFreq = float64(count)
 			}
 		}
 	}
+	scores.PersonalScore = personalFreq * weights.PersonalWeight
 
 	// ============================================================
 	// 3. TRENDING SCORE
 	// ============================================================
 	// For keyword suggestions: Use Redis ZSET score
-	// trending_score = redis_score * 1.0
+	trendingZ := 0.0
 	if candidate.Type == models.SuggestionTypeKeyword {
 		if trendingScore, ok := trendingKeywords[normalizedText]; ok {
-			scores.TrendingScore = trendingScore * 1.0
+			trendingZ = trendingScore
 		}
 	}
 	// For world suggestions, trending score is 0 (could be extended to track world popularity)
+	scores.TrendingScore = trendingZ * weights.TrendingWeight
 
 	// ============================================================
 	// 4. FUZZY SCORE
 	// ============================================================
 	// For world suggestions: similarity = pg_trgm similarity (0-1)
-	// fuzzy_score = similarity * 10
 	// For keyword suggestions: only if using fuzzy corrections
-	if candidate.Source == models.SourceFuzzy || candidate.Source == models.SourceWorldTitle {
-		scores.FuzzyScore = candidate.Similarity * 10.0
+	fuzzySim := 0.0
+	if fuzzyMode != models.FuzzyOff && (candidate.Source == models.SourceFuzzy || candidate.Source == models.SourceWorldTitle) {
+		fuzzySim = candidate.Similarity
 	}
+	scores.FuzzyScore = fuzzySim * weights.FuzzyWeight
 
 	// ============================================================
-	// 5. CTR SCORE (Optional - Basic Implementation)
+	// 5. CTR SCORE
 	// ============================================================
-	// ctr = clicks / GREATEST(impressions, 1)
-	// ctr_score = ctr * 50
+	// ctr_score = wilson_lower_bound(clicks, impressions) * weights.CTRWeight
 	//
-	// Note: This is a simplified implementation. In production, you would:
-	// - Cache CTR stats
-	// - Use time-weighted CTR
-	// - Consider position bias correction
-	ctrStats, err := s.logsRepo.GetCTRStats(ctx, candidate.Text, candidate.Type)
-	if err == nil && ctrStats.Impressions > 0 {
-		ctr := float64(ctrStats.Clicks) / float64(ctrStats.Impressions)
-		scores.CTRScore = ctr * 50.0
+	// The smoothed CTR itself was already fetched for every candidate in
+	// one batched, cached round-trip by CTRFeatureProvider - see
+	// RankSuggestions.
+	ctrSmoothed := 0.0
+	key := repositories.SuggestionKey{Suggestion: candidate.Text, SuggestionType: candidate.Type}
+	if smoothed, ok := ctrScores[key]; ok {
+		ctrSmoothed = smoothed
 	}
+	scores.CTRScore = ctrSmoothed * weights.CTRWeight
 
 	// ============================================================
 	// FINAL SCORE
 	// ============================================================
-	scores.FinalScore = scores.PrefixScore +
-		scores.PersonalScore +
-		scores.TrendingScore +
-		scores.FuzzyScore +
-		scores.CTRScore
+	// Blended through RankerModel.Score rather than summed here directly,
+	// so the static-formula path and the learned-ranker path can never
+	// drift apart - this *is* the formula described in the package doc
+	// comment above, just with weights supplied by the A/B bucket.
+	scores.FinalScore = weights.Score(models.RankerFeatures{
+		Prefix:       prefixFeature,
+		PersonalFreq: personalFreq,
+		TrendingZ:    trendingZ,
+		FuzzySim:     fuzzySim,
+		CTRSmoothed:  ctrSmoothed,
+	})
 
 	return scores, nil
 }
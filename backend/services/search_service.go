@@ -5,8 +5,10 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/worlds-search/backend/backends"
 	"github.com/worlds-search/backend/models"
 	"github.com/worlds-search/backend/repositories"
+	"github.com/worlds-search/backend/telemetry"
 	"github.com/worlds-search/backend/utils"
 )
 
@@ -15,18 +17,36 @@ type SearchService struct {
 	worldsRepo *repositories.WorldsRepository
 	searchRepo *repositories.SearchRepository
 	logsRepo   *repositories.LogsRepository
+
+	// backend is the configured SEARCH_BACKEND implementation. When it's
+	// anything other than pg_trgm, SearchWorlds routes through it instead
+	// of WorldsRepository.SearchCombined so full-text relevance scoring
+	// (BM25, etc.) is used for the results page.
+	backend backends.SearchBackend
+
+	// clickQueue is nil by default (config.Config.LogQueueBackend
+	// "inprocess"), in which case LogClick writes straight to Postgres via
+	// LogsRepository.InsertClick. When LogQueueBackend is "redis", main.go
+	// wires repositories.RedisLogQueue in here instead, so LogClick buffers
+	// through Redis for cmd/logs-runner to drain, same as impressions.
+	clickQueue repositories.ClickQueue
 }
 
-// NewSearchService creates a new SearchService
+// NewSearchService creates a new SearchService. clickQueue may be nil -
+// see the clickQueue field doc comment.
 func NewSearchService(
 	worldsRepo *repositories.WorldsRepository,
 	searchRepo *repositories.SearchRepository,
 	logsRepo *repositories.LogsRepository,
+	backend backends.SearchBackend,
+	clickQueue repositories.ClickQueue,
 ) *SearchService {
 	return &SearchService{
 		worldsRepo: worldsRepo,
 		searchRepo: searchRepo,
 		logsRepo:   logsRepo,
+		backend:    backend,
+		clickQueue: clickQueue,
 	}
 }
 
@@ -88,8 +108,19 @@ func (s *SearchService) LogClick(ctx context.Context, click *models.ClickInput)
 		}
 	}
 
-	// Insert click record
-	if err := s.logsRepo.InsertClick(
+	if s.clickQueue != nil {
+		if err := s.clickQueue.EnqueueClick(ctx, repositories.ClickRecord{
+			UserID:            userID,
+			Keyword:           click.Keyword,
+			NormalizedKeyword: normalizedKeyword,
+			ClickedSuggestion: click.Suggestion,
+			SuggestionType:    click.SuggestionType,
+			WorldID:           worldID,
+			Position:          click.Position,
+		}); err != nil {
+			return err
+		}
+	} else if err := s.logsRepo.InsertClick(
 		ctx,
 		userID,
 		click.Keyword,
@@ -110,9 +141,20 @@ func (s *SearchService) LogClick(ctx context.Context, click *models.ClickInput)
 	return nil
 }
 
-// SearchWorlds searches for worlds matching the keyword
-func (s *SearchService) SearchWorlds(ctx context.Context, keyword string, userID *uuid.UUID) (*SearchResults, error) {
+// SearchWorlds searches for worlds matching the keyword. fuzzyMode only
+// affects the pg_trgm/RRF paths - it's a no-op when a non-pg_trgm
+// SEARCH_BACKEND is configured, since those backends don't expose a
+// per-query fuzziness knob yet. useRRF routes through
+// WorldsRepository.SearchRRF instead of SearchCombined; it's ignored when
+// a non-pg_trgm SEARCH_BACKEND is configured, since RRF fuses Postgres'
+// own ranking signals and doesn't apply to those backends.
+func (s *SearchService) SearchWorlds(ctx context.Context, keyword string, userID *uuid.UUID, fuzzyMode models.FuzzyMode, useRRF bool) (*SearchResults, error) {
+	ctx, span := telemetry.StartSpan(ctx, "search_worlds")
+	defer span.End()
 	startTime := time.Now()
+	defer func() {
+		telemetry.RecordLatency(ctx, s.backendSourceLabel(useRRF), time.Since(startTime).Seconds(), userID)
+	}()
 
 	normalizedKeyword := utils.NormalizeKeyword(keyword)
 
@@ -124,13 +166,175 @@ func (s *SearchService) SearchWorlds(ctx context.Context, keyword string, userID
 		}, nil
 	}
 
-	// Use combined search (prefix + fuzzy + contains)
-	results, err := s.worldsRepo.SearchCombined(ctx, normalizedKeyword, 20)
+	worlds, err := s.searchWorldCards(ctx, normalizedKeyword, 20, fuzzyMode, useRRF)
+	if err != nil {
+		return nil, err
+	}
+
+	return &SearchResults{
+		Keyword:   keyword,
+		Worlds:    worlds,
+		ElapsedMs: time.Since(startTime).Milliseconds(),
+	}, nil
+}
+
+// backendSourceLabel is the search.source attribute value SearchWorlds
+// records its latency under - the configured backend's name, "rrf" when
+// useRRF was honored, or "trgm" for the default WorldsRepository.
+// SearchCombined path.
+func (s *SearchService) backendSourceLabel(useRRF bool) string {
+	if s.backend != nil && s.backend.Name() != "pg_trgm" {
+		return s.backend.Name()
+	}
+	if useRRF {
+		return "rrf"
+	}
+	return "trgm"
+}
+
+// searchWorldCards runs the configured SEARCH_BACKEND (falling back to
+// WorldsRepository.SearchCombined, or SearchRRF when useRRF is set and no
+// non-pg_trgm backend is configured) and converts the results to WorldCard
+// format. fuzzyMode is passed through to SearchCombined/SearchRRF;
+// non-pg_trgm backends ignore it for now.
+func (s *SearchService) searchWorldCards(ctx context.Context, normalizedKeyword string, limit int, fuzzyMode models.FuzzyMode, useRRF bool) ([]models.WorldCard, error) {
+	if s.backend != nil && s.backend.Name() != "pg_trgm" {
+		matches, err := s.backend.FullTextSearch(ctx, normalizedKeyword, limit)
+		if err != nil {
+			return nil, err
+		}
+		worlds := make([]models.WorldCard, len(matches))
+		for i, m := range matches {
+			worlds[i] = models.WorldCard{
+				ID:          m.World.ID,
+				Title:       m.World.Title,
+				Description: utils.TruncateString(m.World.Description, 200),
+				CreatedAt:   m.World.CreatedAt,
+			}
+		}
+		return worlds, nil
+	}
+
+	if useRRF {
+		results, err := s.worldsRepo.SearchRRF(ctx, normalizedKeyword, limit, fuzzyMode, 0)
+		if err != nil {
+			return nil, err
+		}
+		return worldCardsFromSimilarity(results), nil
+	}
+
+	results, err := s.worldsRepo.SearchCombined(ctx, normalizedKeyword, limit, fuzzyMode)
+	if err != nil {
+		return nil, err
+	}
+	return worldCardsFromSimilarity(results), nil
+}
+
+// worldCardsFromSimilarity converts a WorldWithSimilarity slice (shared by
+// SearchCombined and SearchRRF) to WorldCard format.
+func worldCardsFromSimilarity(results []repositories.WorldWithSimilarity) []models.WorldCard {
+	worlds := make([]models.WorldCard, len(results))
+	for i, ws := range results {
+		worlds[i] = models.WorldCard{
+			ID:          ws.World.ID,
+			Title:       ws.World.Title,
+			Description: utils.TruncateString(ws.World.Description, 200),
+			CreatedAt:   ws.World.CreatedAt,
+		}
+	}
+	return worlds
+}
+
+// defaultSearchSize is the page size used when SearchRequest.Size is unset
+// or invalid.
+const defaultSearchSize = 20
+
+// maxSearchSize caps SearchRequest.Size so a caller can't force a full
+// table scan through the public API.
+const maxSearchSize = 100
+
+// SearchWorldsDSL runs the structured search request DSL (POST
+// /api/search/results): keyword plus filters, sort, from/size pagination,
+// and requested facets.
+func (s *SearchService) SearchWorldsDSL(ctx context.Context, req models.SearchRequest) (*models.SearchResultsV2, error) {
+	startTime := time.Now()
+
+	size := req.Size
+	if size <= 0 {
+		size = defaultSearchSize
+	}
+	if size > maxSearchSize {
+		size = maxSearchSize
+	}
+	from := req.From
+	if from < 0 {
+		from = 0
+	}
+
+	normalizedKeyword := utils.NormalizeKeyword(req.Keyword)
+
+	results, total, err := s.worldsRepo.SearchWithOptions(ctx, normalizedKeyword, req.Filters, req.Sort, from, size)
+	if err != nil {
+		return nil, err
+	}
+
+	hits := make([]models.SearchHit, len(results))
+	for i, ws := range results {
+		highlights := map[string][]string{}
+		if snippets := utils.Highlight(ws.World.Title, normalizedKeyword, 3); snippets != nil {
+			highlights["title"] = snippets
+		}
+		if snippets := utils.Highlight(ws.World.Description, normalizedKeyword, 3); snippets != nil {
+			highlights["description"] = snippets
+		}
+		hits[i] = models.SearchHit{
+			World: models.WorldCard{
+				ID:          ws.World.ID,
+				Title:       ws.World.Title,
+				Description: utils.TruncateString(ws.World.Description, 200),
+				CreatedAt:   ws.World.CreatedAt,
+			},
+			Highlights: highlights,
+		}
+	}
+
+	facets := make(map[string][]models.FacetBucket, len(req.Facets))
+	for _, name := range req.Facets {
+		if name != "created_at" {
+			// tag/owner facets aren't backed by a column yet
+			facets[name] = []models.FacetBucket{}
+			continue
+		}
+		buckets, err := s.worldsRepo.GetCreatedAtFacet(ctx, normalizedKeyword)
+		if err != nil {
+			return nil, err
+		}
+		facets[name] = buckets
+	}
+
+	return &models.SearchResultsV2{
+		Keyword:   req.Keyword,
+		Hits:      hits,
+		Total:     total,
+		Facets:    facets,
+		From:      from,
+		Size:      size,
+		ElapsedMs: time.Since(startTime).Milliseconds(),
+	}, nil
+}
+
+// SearchWorldsQuery runs a structured searchparser query string - field
+// filters (title:foo), quoted phrases, created:>date ranges, and -negation
+// - against the worlds table. It's the entry point for the richer query
+// language GET /api/search/results' plain keyword param doesn't support.
+func (s *SearchService) SearchWorldsQuery(ctx context.Context, query string, limit int) (*SearchResults, error) {
+	startTime := time.Now()
+
+	results, err := s.worldsRepo.SearchByQuery(ctx, query, limit)
 	if err != nil {
 		return nil, err
 	}
 
-	// Convert to WorldCard format
 	worlds := make([]models.WorldCard, len(results))
 	for i, ws := range results {
 		worlds[i] = models.WorldCard{
@@ -142,7 +346,7 @@ func (s *SearchService) SearchWorlds(ctx context.Context, keyword string, userID
 	}
 
 	return &SearchResults{
-		Keyword:   keyword,
+		Keyword:   query,
 		Worlds:    worlds,
 		ElapsedMs: time.Since(startTime).Milliseconds(),
 	}, nil
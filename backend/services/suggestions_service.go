@@ -2,6 +2,7 @@ package services
 
 import (
 	"context"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/worlds-search/backend/models"
@@ -9,113 +10,76 @@ import (
 	"github.com/worlds-search/backend/utils"
 )
 
+const (
+	// perSourceTimeout bounds how long any single candidate source gets.
+	perSourceTimeout = 40 * time.Millisecond
+	// overallTimeout bounds the whole fan-out, regardless of how many
+	// sources are still running.
+	overallTimeout = 80 * time.Millisecond
+)
+
 // SuggestionsService handles autocomplete suggestions
 type SuggestionsService struct {
-	worldsRepo  *repositories.WorldsRepository
-	searchRepo  *repositories.SearchRepository
-	logsRepo    *repositories.LogsRepository
+	worldsRepo     *repositories.WorldsRepository
+	searchRepo     *repositories.SearchRepository
+	logsRepo       *repositories.LogsRepository
 	rankingService *RankingService
+	impressionSink repositories.ImpressionQueue
+	sourceStats    *candidateSourceStats
 }
 
-// NewSuggestionsService creates a new SuggestionsService
+// NewSuggestionsService creates a new SuggestionsService. impressionSink is
+// repositories.ImpressionSink by default (config.Config.LogQueueBackend
+// "inprocess") or repositories.RedisLogQueue when it's "redis" - see
+// main.go.
 func NewSuggestionsService(
 	worldsRepo *repositories.WorldsRepository,
 	searchRepo *repositories.SearchRepository,
 	logsRepo *repositories.LogsRepository,
 	rankingService *RankingService,
+	impressionSink repositories.ImpressionQueue,
 ) *SuggestionsService {
 	return &SuggestionsService{
 		worldsRepo:     worldsRepo,
 		searchRepo:     searchRepo,
 		logsRepo:       logsRepo,
 		rankingService: rankingService,
+		impressionSink: impressionSink,
+		sourceStats:    newCandidateSourceStats(),
 	}
 }
 
-// GetSuggestions returns ranked autocomplete suggestions for a keyword
-// This is the main entry point for the suggestions API
-func (s *SuggestionsService) GetSuggestions(ctx context.Context, keyword string, userID *uuid.UUID) (*models.SuggestionsResponse, error) {
+// candidateResult is what each fan-out goroutine in GetSuggestions sends
+// back on the shared results channel.
+type candidateResult struct {
+	source     string
+	candidates []models.SuggestionCandidate
+	trending   map[string]float64
+	err        error
+	timedOut   bool
+}
+
+// GetSuggestions returns ranked autocomplete suggestions for a keyword,
+// plus whether any candidate source errored or was dropped due to its
+// deadline firing (the caller surfaces this as X-Suggestions-Partial).
+// This is the main entry point for the suggestions API.
+func (s *SuggestionsService) GetSuggestions(ctx context.Context, keyword string, userID *uuid.UUID, fuzzyMode models.FuzzyMode) (*models.SuggestionsResponse, bool, error) {
 	normalizedKeyword := utils.NormalizeKeyword(keyword)
-	
+
 	if normalizedKeyword == "" {
 		return &models.SuggestionsResponse{
 			Keyword:     keyword,
 			Suggestions: []models.Suggestion{},
-		}, nil
+		}, false, nil
 	}
 
 	// ============================================================
-	// Step 1: Gather candidates from all sources
+	// Step 1: Gather candidates from all sources concurrently
 	// ============================================================
-	candidates := make([]models.SuggestionCandidate, 0, 50)
-
-	// A) Keyword candidates from personal history
-	if userID != nil {
-		historyKeywords, err := s.searchRepo.GetUserHistoryKeywords(ctx, *userID, normalizedKeyword, 10)
-		if err == nil {
-			for _, kf := range historyKeywords {
-				candidates = append(candidates, models.SuggestionCandidate{
-					Type:   models.SuggestionTypeKeyword,
-					Text:   kf.Keyword,
-					Source: models.SourceUserHistory,
-				})
-			}
-		}
-	}
-
-	// B) Keyword candidates from global trending (Redis ZSET)
-	trendingKeywords, err := s.logsRepo.GetTrendingKeywords(ctx, normalizedKeyword, 10)
-	trendingMap := make(map[string]float64)
-	if err == nil {
-		for _, tk := range trendingKeywords {
-			trendingMap[tk.Keyword] = tk.Score
-			candidates = append(candidates, models.SuggestionCandidate{
-				Type:   models.SuggestionTypeKeyword,
-				Text:   tk.Keyword,
-				Source: models.SourceTrending,
-			})
-		}
-	}
-
-	// C) World candidates from prefix match
-	prefixWorlds, err := s.worldsRepo.SearchByPrefix(ctx, normalizedKeyword, 10)
-	if err == nil {
-		for _, w := range prefixWorlds {
-			worldID := w.ID
-			candidates = append(candidates, models.SuggestionCandidate{
-				Type:       models.SuggestionTypeWorld,
-				Text:       w.Title,
-				Source:     models.SourceWorldTitle,
-				WorldID:    &worldID,
-				Similarity: 1.0, // Prefix match has perfect similarity
-			})
-		}
-	}
-
-	// D) World candidates from fuzzy match (pg_trgm)
-	fuzzyWorlds, err := s.worldsRepo.SearchByFuzzy(ctx, normalizedKeyword, 10)
-	if err == nil {
-		for _, ws := range fuzzyWorlds {
-			worldID := ws.World.ID
-			// Check if already added from prefix match
-			alreadyAdded := false
-			for _, c := range candidates {
-				if c.WorldID != nil && *c.WorldID == worldID {
-					alreadyAdded = true
-					break
-				}
-			}
-			if !alreadyAdded {
-				candidates = append(candidates, models.SuggestionCandidate{
-					Type:       models.SuggestionTypeWorld,
-					Text:       ws.World.Title,
-					Source:     models.SourceFuzzy,
-					WorldID:    &worldID,
-					Similarity: ws.Similarity,
-				})
-			}
-		}
-	}
+	// Each source runs against its own 40ms context.WithTimeout under an
+	// overall 80ms deadlineTimer budget, so one slow Postgres query can't stall
+	// the whole call - we just return whatever completed in time.
+	candidates, trendingMap, partial := s.gatherCandidates(ctx, normalizedKeyword, userID)
 
 	// ============================================================
 	// Step 2: Score and rank all candidates
@@ -126,9 +90,10 @@ func (s *SuggestionsService) GetSuggestions(ctx context.Context, keyword string,
 		normalizedKeyword,
 		userID,
 		trendingMap,
+		fuzzyMode,
 	)
 	if err != nil {
-		return nil, err
+		return nil, partial, err
 	}
 
 	// ============================================================
@@ -169,13 +134,180 @@ func (s *SuggestionsService) GetSuggestions(ctx context.Context, keyword string,
 		}
 	}
 
-	// Log impressions asynchronously (fire and forget)
-	go func() {
-		_ = s.logsRepo.InsertImpressionsBatch(context.Background(), impressions)
-	}()
+	// Hand impressions off to the bounded sink instead of spawning a
+	// goroutine per request - the sink coalesces them across requests and
+	// drops records once its queue is full rather than piling up
+	// unbounded connections to Postgres.
+	s.impressionSink.EnqueueBatch(impressions)
 
 	return &models.SuggestionsResponse{
 		Keyword:     keyword,
 		Suggestions: suggestions,
-	}, nil
+	}, partial, nil
+}
+
+// gatherCandidates runs the four candidate sources concurrently, each
+// under its own perSourceTimeout-bounded context, and returns whatever
+// completed before the overallTimeout fires. partial is true if any
+// source errored or was dropped by its deadline.
+func (s *SuggestionsService) gatherCandidates(
+	ctx context.Context,
+	normalizedKeyword string,
+	userID *uuid.UUID,
+) ([]models.SuggestionCandidate, map[string]float64, bool) {
+	overall := newDeadlineTimer(overallTimeout)
+	defer overall.Stop()
+
+	results := make(chan candidateResult, 4)
+	inFlight := 0
+
+	if userID != nil {
+		inFlight++
+		go s.fetchUserHistory(ctx, normalizedKeyword, *userID, results)
+	}
+	inFlight++
+	go s.fetchTrending(ctx, normalizedKeyword, results)
+	inFlight++
+	go s.fetchPrefix(ctx, normalizedKeyword, results)
+	inFlight++
+	go s.fetchFuzzy(ctx, normalizedKeyword, results)
+
+	candidates := make([]models.SuggestionCandidate, 0, 50)
+	trendingMap := make(map[string]float64)
+	seenWorlds := make(map[uuid.UUID]bool)
+	partial := false
+
+collect:
+	for i := 0; i < inFlight; i++ {
+		select {
+		case res := <-results:
+			if res.err != nil || res.timedOut {
+				partial = true
+				continue
+			}
+			if res.trending != nil {
+				trendingMap = res.trending
+			}
+			for _, c := range res.candidates {
+				if c.WorldID != nil {
+					if seenWorlds[*c.WorldID] {
+						continue
+					}
+					seenWorlds[*c.WorldID] = true
+				}
+				candidates = append(candidates, c)
+			}
+		case <-overall.C:
+			// Whatever hasn't reported back by now is dropped; the
+			// goroutines themselves still respect their own per-source
+			// deadline and exit on their own.
+			partial = true
+			break collect
+		}
+	}
+
+	return candidates, trendingMap, partial
+}
+
+// fetchUserHistory is the goroutine body for the personal-history
+// candidate source.
+func (s *SuggestionsService) fetchUserHistory(ctx context.Context, normalizedKeyword string, userID uuid.UUID, results chan<- candidateResult) {
+	start := time.Now()
+	sctx, cancel := context.WithTimeout(ctx, perSourceTimeout)
+	defer cancel()
+
+	historyKeywords, err := s.searchRepo.GetUserHistoryKeywords(sctx, userID, normalizedKeyword, 10)
+	timedOut := sctx.Err() == context.DeadlineExceeded
+	s.sourceStats.record(sourceUserHistory, time.Since(start), err, timedOut)
+
+	candidates := make([]models.SuggestionCandidate, 0, len(historyKeywords))
+	for _, kf := range historyKeywords {
+		candidates = append(candidates, models.SuggestionCandidate{
+			Type:   models.SuggestionTypeKeyword,
+			Text:   kf.Keyword,
+			Source: models.SourceUserHistory,
+		})
+	}
+	results <- candidateResult{source: sourceUserHistory, candidates: candidates, err: err, timedOut: timedOut}
+}
+
+// fetchTrending is the goroutine body for the global-trending candidate
+// source. It also returns the keyword -> trending score map that
+// RankingService needs for its trending_score term.
+func (s *SuggestionsService) fetchTrending(ctx context.Context, normalizedKeyword string, results chan<- candidateResult) {
+	start := time.Now()
+	sctx, cancel := context.WithTimeout(ctx, perSourceTimeout)
+	defer cancel()
+
+	trendingKeywords, err := s.logsRepo.GetTrendingKeywords(sctx, normalizedKeyword, 10)
+	timedOut := sctx.Err() == context.DeadlineExceeded
+	s.sourceStats.record(sourceTrending, time.Since(start), err, timedOut)
+
+	trendingMap := make(map[string]float64, len(trendingKeywords))
+	candidates := make([]models.SuggestionCandidate, 0, len(trendingKeywords))
+	for _, tk := range trendingKeywords {
+		trendingMap[tk.Keyword] = tk.Score
+		candidates = append(candidates, models.SuggestionCandidate{
+			Type:   models.SuggestionTypeKeyword,
+			Text:   tk.Keyword,
+			Source: models.SourceTrending,
+		})
+	}
+	results <- candidateResult{source: sourceTrending, candidates: candidates, trending: trendingMap, err: err, timedOut: timedOut}
+}
+
+// fetchPrefix is the goroutine body for the prefix-match candidate source.
+func (s *SuggestionsService) fetchPrefix(ctx context.Context, normalizedKeyword string, results chan<- candidateResult) {
+	start := time.Now()
+	sctx, cancel := context.WithTimeout(ctx, perSourceTimeout)
+	defer cancel()
+
+	prefixWorlds, err := s.worldsRepo.SearchByPrefix(sctx, normalizedKeyword, 10)
+	timedOut := sctx.Err() == context.DeadlineExceeded
+	s.sourceStats.record(sourcePrefix, time.Since(start), err, timedOut)
+
+	candidates := make([]models.SuggestionCandidate, 0, len(prefixWorlds))
+	for _, w := range prefixWorlds {
+		worldID := w.ID
+		candidates = append(candidates, models.SuggestionCandidate{
+			Type:       models.SuggestionTypeWorld,
+			Text:       w.Title,
+			Source:     models.SourceWorldTitle,
+			WorldID:    &worldID,
+			Similarity: 1.0, // Prefix match has perfect similarity
+		})
+	}
+	results <- candidateResult{source: sourcePrefix, candidates: candidates, err: err, timedOut: timedOut}
+}
+
+// fetchFuzzy is the goroutine body for the pg_trgm fuzzy-match candidate
+// source. Dedup against prefix matches happens in gatherCandidates once
+// both sources have reported, since either can arrive first.
+func (s *SuggestionsService) fetchFuzzy(ctx context.Context, normalizedKeyword string, results chan<- candidateResult) {
+	start := time.Now()
+	sctx, cancel := context.WithTimeout(ctx, perSourceTimeout)
+	defer cancel()
+
+	fuzzyWorlds, err := s.worldsRepo.SearchByFuzzy(sctx, normalizedKeyword, 10)
+	timedOut := sctx.Err() == context.DeadlineExceeded
+	s.sourceStats.record(sourceFuzzy, time.Since(start), err, timedOut)
+
+	candidates := make([]models.SuggestionCandidate, 0, len(fuzzyWorlds))
+	for _, ws := range fuzzyWorlds {
+		worldID := ws.World.ID
+		candidates = append(candidates, models.SuggestionCandidate{
+			Type:       models.SuggestionTypeWorld,
+			Text:       ws.World.Title,
+			Source:     models.SourceFuzzy,
+			WorldID:    &worldID,
+			Similarity: ws.Similarity,
+		})
+	}
+	results <- candidateResult{source: sourceFuzzy, candidates: candidates, err: err, timedOut: timedOut}
+}
+
+// SourceStats returns a point-in-time snapshot of per-source call
+// latency/timeout/error counters, for a future debug/metrics endpoint.
+func (s *SuggestionsService) SourceStats() map[string]SourceStats {
+	return s.sourceStats.Snapshot()
 }
@@ -0,0 +1,222 @@
+// Package telemetry wires up OpenTelemetry metrics and tracing for the
+// search/ranking pipeline: a latency histogram broken down by source
+// (trgm, bigram, rrf, bleve, es, the configured backend's name, rank,
+// ctr_lookup), a counter for which candidate source each ranked suggestion
+// came from, and a tracer for wrapping
+// individual repository calls. Modeled on the pkgsite search-latency
+// pattern - a Float64 histogram tagged by a source label with a default
+// distribution view - rather than inventing a bespoke metrics shape.
+package telemetry
+
+import (
+	"context"
+	"hash/fnv"
+	"net/http"
+	"strconv"
+
+	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/prometheus"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/metric"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const instrumentationName = "github.com/worlds-search/backend"
+
+// userBuckets is how many buckets a userID is hashed into for the
+// user.bucket metric attribute, so per-cohort ranker performance (the A/B
+// split RankingService already does for weight selection) can be compared
+// in a dashboard without every distinct user blowing up metric cardinality.
+const userBuckets = 16
+
+var (
+	tracer             trace.Tracer
+	searchLatency      metric.Float64Histogram
+	sourceContribution metric.Int64Counter
+)
+
+func init() {
+	// Registered against the global (no-op by default) providers so every
+	// call site works even before Init runs, e.g. in contexts where main
+	// hasn't wired up a real exporter (future test binaries, etc.).
+	tracer = otel.Tracer(instrumentationName)
+	meter := otel.Meter(instrumentationName)
+
+	var err error
+	searchLatency, err = meter.Float64Histogram(
+		"search.latency",
+		metric.WithDescription("Latency of a search/ranking pipeline stage, by source"),
+		metric.WithUnit("s"),
+	)
+	if err != nil {
+		otel.Handle(err)
+	}
+	sourceContribution, err = meter.Int64Counter(
+		"search.suggestion_source",
+		metric.WithDescription("Count of ranked suggestion candidates contributed by each candidate source"),
+	)
+	if err != nil {
+		otel.Handle(err)
+	}
+}
+
+// Init configures the global TracerProvider and MeterProvider for
+// serviceName, selecting the trace exporter via exporterKind ("otlp",
+// "stdout", or anything else for a no-op tracer). Metrics are always
+// exported via the Prometheus bridge - call Handler to mount /metrics.
+// Returns a shutdown func to flush the exporter during graceful shutdown.
+func Init(ctx context.Context, exporterKind, serviceName string) (shutdown func(context.Context) error, err error) {
+	res, err := resource.Merge(
+		resource.Default(),
+		resource.NewWithAttributes(semconv.SchemaURL, semconv.ServiceName(serviceName)),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	traceShutdown, err := initTracing(ctx, exporterKind, res)
+	if err != nil {
+		return nil, err
+	}
+
+	metricShutdown, err := initMetrics(res)
+	if err != nil {
+		return nil, err
+	}
+
+	tracer = otel.Tracer(instrumentationName)
+
+	return func(shutdownCtx context.Context) error {
+		if err := traceShutdown(shutdownCtx); err != nil {
+			return err
+		}
+		return metricShutdown(shutdownCtx)
+	}, nil
+}
+
+func initTracing(ctx context.Context, exporterKind string, res *resource.Resource) (func(context.Context) error, error) {
+	var spanExporter sdktrace.SpanExporter
+	var err error
+
+	switch exporterKind {
+	case "otlp":
+		spanExporter, err = otlptracegrpc.New(ctx)
+	case "stdout":
+		spanExporter, err = stdouttrace.New()
+	default:
+		// No exporter configured - spans are still created (so code
+		// doesn't need exporterKind-specific branches) but go nowhere.
+		otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithResource(res)))
+		return func(context.Context) error { return nil }, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	tp := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(spanExporter),
+		sdktrace.WithResource(res),
+	)
+	otel.SetTracerProvider(tp)
+	return tp.Shutdown, nil
+}
+
+func initMetrics(res *resource.Resource) (func(context.Context) error, error) {
+	exporter, err := prometheus.New()
+	if err != nil {
+		return nil, err
+	}
+
+	mp := sdkmetric.NewMeterProvider(
+		sdkmetric.WithReader(exporter),
+		sdkmetric.WithResource(res),
+		// Matches the pkgsite search-latency pattern: a fixed-bucket
+		// distribution view instead of the SDK's generic exponential
+		// default, so p50/p90/p99 panels line up across deploys.
+		sdkmetric.WithView(sdkmetric.NewView(
+			sdkmetric.Instrument{Name: "search.latency"},
+			sdkmetric.Stream{Aggregation: sdkmetric.AggregationExplicitBucketHistogram{
+				Boundaries: []float64{.001, .005, .01, .025, .05, .1, .25, .5, 1},
+			}},
+		)),
+	)
+	otel.SetMeterProvider(mp)
+
+	meter := mp.Meter(instrumentationName)
+	searchLatency, err = meter.Float64Histogram(
+		"search.latency",
+		metric.WithDescription("Latency of a search/ranking pipeline stage, by source"),
+		metric.WithUnit("s"),
+	)
+	if err != nil {
+		return nil, err
+	}
+	sourceContribution, err = meter.Int64Counter(
+		"search.suggestion_source",
+		metric.WithDescription("Count of ranked suggestion candidates contributed by each candidate source"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return mp.Shutdown, nil
+}
+
+// Handler returns the Prometheus scrape handler for /metrics. The otel
+// Prometheus exporter registers its collector into the default registry,
+// so promhttp's default handler is all that's needed here.
+func Handler() http.Handler {
+	return promhttp.Handler()
+}
+
+// userBucket hashes userID into [0, userBuckets) for the user.bucket
+// attribute. Anonymous (nil) traffic is reported under bucket "anon"
+// rather than being hashed, since there's nothing stable to bucket on.
+func userBucket(userID *uuid.UUID) string {
+	if userID == nil {
+		return "anon"
+	}
+	h := fnv.New32a()
+	_, _ = h.Write(userID[:])
+	return strconv.Itoa(int(h.Sum32() % userBuckets))
+}
+
+// RecordLatency records a pipeline-stage duration against the
+// search.latency histogram, tagged by source and the caller's A/B/user
+// bucket.
+func RecordLatency(ctx context.Context, source string, seconds float64, userID *uuid.UUID) {
+	searchLatency.Record(ctx, seconds,
+		metric.WithAttributes(
+			attribute.String("search.source", source),
+			attribute.String("user.bucket", userBucket(userID)),
+		),
+	)
+}
+
+// RecordSourceContribution increments the per-source counter once per
+// call. RankingService.RankSuggestions calls it for every ranked
+// candidate, so candidates later removed by deduplication or the top-N
+// cut in SuggestionsService.GetSuggestions are counted too.
+func RecordSourceContribution(ctx context.Context, source string, userID *uuid.UUID) {
+	sourceContribution.Add(ctx, 1,
+		metric.WithAttributes(
+			attribute.String("search.source", source),
+			attribute.String("user.bucket", userBucket(userID)),
+		),
+	)
+}
+
+// StartSpan starts a span named "worlds_search.<name>" for wrapping a
+// single repository/Redis call, so slow hops show up in a trace instead of
+// only in the aggregate latency histogram.
+func StartSpan(ctx context.Context, name string) (context.Context, trace.Span) {
+	return tracer.Start(ctx, "worlds_search."+name)
+}
@@ -0,0 +1,59 @@
+// Package concurrency provides small fan-out helpers shared across
+// services that need to run a fixed batch of jobs with bounded
+// parallelism, modeled on grafana/dskit's concurrency package.
+package concurrency
+
+import (
+	"context"
+	"sync"
+)
+
+// ForEachJob runs job(ctx, idx) for every idx in [0, jobs) across up to
+// concurrency goroutines, blocking until all of them have returned. The
+// context passed to job is canceled as soon as any invocation returns a
+// non-nil error, and that first error is what ForEachJob itself returns -
+// mirroring dskit's job-index pattern. A job that wants a per-mode error
+// to surface without aborting its siblings (e.g. CompareService's
+// per-mode fan-out) should record that error itself and return nil.
+func ForEachJob(ctx context.Context, jobs int, concurrency int, job func(ctx context.Context, idx int) error) error {
+	if jobs == 0 {
+		return nil
+	}
+	if concurrency <= 0 || concurrency > jobs {
+		concurrency = jobs
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	indexes := make(chan int, jobs)
+	for i := 0; i < jobs; i++ {
+		indexes <- i
+	}
+	close(indexes)
+
+	var (
+		wg       sync.WaitGroup
+		errOnce  sync.Once
+		firstErr error
+	)
+	for w := 0; w < concurrency; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for idx := range indexes {
+				if ctx.Err() != nil {
+					return
+				}
+				if err := job(ctx, idx); err != nil {
+					errOnce.Do(func() { firstErr = err })
+					cancel()
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	return firstErr
+}
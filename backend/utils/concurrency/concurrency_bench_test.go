@@ -0,0 +1,39 @@
+package concurrency
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// jobLatency is how long each simulated mode "search" takes - representative
+// of compareModeTimeout-class latencies without actually taking that long
+// to run the benchmark.
+const jobLatency = 20 * time.Millisecond
+
+// BenchmarkForEachJobParallel fans jobCount jobs out across as many
+// goroutines as there are jobs, the same shape CompareService.gatherModes
+// uses for "both"/"all". End-to-end time should land close to jobLatency
+// (max(mode)) regardless of jobCount, not jobCount*jobLatency (sum(mode)).
+func BenchmarkForEachJobParallel(b *testing.B) {
+	const jobCount = 5
+	for i := 0; i < b.N; i++ {
+		_ = ForEachJob(context.Background(), jobCount, jobCount, func(ctx context.Context, idx int) error {
+			time.Sleep(jobLatency)
+			return nil
+		})
+	}
+}
+
+// BenchmarkForEachJobSequential runs the same jobCount jobs one at a time
+// (concurrency of 1), the sum(mode) baseline BenchmarkForEachJobParallel
+// is meant to beat.
+func BenchmarkForEachJobSequential(b *testing.B) {
+	const jobCount = 5
+	for i := 0; i < b.N; i++ {
+		_ = ForEachJob(context.Background(), jobCount, 1, func(ctx context.Context, idx int) error {
+			time.Sleep(jobLatency)
+			return nil
+		})
+	}
+}
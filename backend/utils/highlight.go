@@ -0,0 +1,45 @@
+package utils
+
+import "strings"
+
+// Highlight returns up to maxSnippets occurrences of keyword in text,
+// each wrapped in <em> tags with a little surrounding context, mirroring
+// the snippet shape search engines like Elasticsearch/Bleve return.
+// Returns nil if keyword doesn't appear in text.
+func Highlight(text, keyword string, maxSnippets int) []string {
+	if keyword == "" || text == "" {
+		return nil
+	}
+
+	lowerText := strings.ToLower(text)
+	lowerKeyword := strings.ToLower(keyword)
+
+	const context = 30
+	var snippets []string
+
+	start := 0
+	for len(snippets) < maxSnippets {
+		idx := strings.Index(lowerText[start:], lowerKeyword)
+		if idx < 0 {
+			break
+		}
+		matchStart := start + idx
+		matchEnd := matchStart + len(keyword)
+
+		snippetStart := matchStart - context
+		if snippetStart < 0 {
+			snippetStart = 0
+		}
+		snippetEnd := matchEnd + context
+		if snippetEnd > len(text) {
+			snippetEnd = len(text)
+		}
+
+		snippet := text[snippetStart:matchStart] + "<em>" + text[matchStart:matchEnd] + "</em>" + text[matchEnd:snippetEnd]
+		snippets = append(snippets, snippet)
+
+		start = matchEnd
+	}
+
+	return snippets
+}